@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/petersenjoern/devenv/internal/bundle"
+	"github.com/petersenjoern/devenv/internal/detector"
+	"github.com/petersenjoern/devenv/internal/installer"
+	"github.com/petersenjoern/devenv/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+const (
+	bundleCategory  = "bundle"
+	bundleDirEnvVar = "DEVENV_BUNDLE_DIR"
+)
+
+var fromBundle string
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Manage offline bundles for air-gapped machines",
+}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create <dir>",
+	Short: "Download every artifact the selected tools need into a bundle",
+	Long: `Download release archives, apt packages, git repositories and declared
+script artifacts for the selected tools (including dependencies) into <dir>,
+together with a manifest and SHA256SUMS file. Copy the directory to the
+offline machine and run 'devenv install --from-bundle <dir>'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selections, err := RunInstallFlow()
+		if err != nil {
+			return installFlowError(err)
+		}
+
+		configPath, err := findConfigPath()
+		if err != nil {
+			return err
+		}
+
+		manifest, err := CreateBundle(args[0], selections, configPath)
+		if err != nil {
+			return fmt.Errorf("creating bundle: %w", err)
+		}
+
+		displayBundleSummary(args[0], manifest)
+		return nil
+	},
+}
+
+// CreateBundle fetches the artifacts of the selected tools and their
+// dependencies into dir.
+func CreateBundle(dir string, selections tui.Selections, configPath string) (bundle.Manifest, error) {
+	toolConfigs, err := LoadToolConfigurations(configPath)
+	if err != nil {
+		return bundle.Manifest{}, fmt.Errorf("failed to load tool configurations: %w", err)
+	}
+
+	plan := CreateInstallationOrchestrator().Plan(selections, toolConfigs)
+
+	creator := &bundle.Creator{
+		Downloader:      newDownloader(),
+		CommandExecutor: &installer.RealCommandExecutor{},
+		Arch:            detector.New().DetectArchitecture(),
+	}
+
+	return creator.Create(dir, plan, toolConfigs)
+}
+
+// runBundleInstall installs the tools recorded in a bundle without network access.
+func runBundleInstall(dir string) error {
+	b, err := openBundle(dir)
+	if err != nil {
+		return err
+	}
+
+	configPath, err := findConfigPath()
+	if err != nil {
+		return err
+	}
+
+	orchestrator := CreateInstallationOrchestrator()
+	useBundle(orchestrator, b)
+
+	results, err := executeInstallationsWith(orchestrator, bundleSelections(b), configPath)
+	if err != nil {
+		return fmt.Errorf("executing installations: %w", err)
+	}
+
+	return finishInstall(results)
+}
+
+// openBundle opens the bundle in dir and checks it was made for this machine.
+func openBundle(dir string) (*bundle.Bundle, error) {
+	b, err := bundle.Open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening bundle: %w", err)
+	}
+
+	if arch := detector.New().DetectArchitecture(); b.Manifest.Arch != "" && b.Manifest.Arch != arch {
+		return nil, fmt.Errorf("bundle was created for %s, this machine is %s", b.Manifest.Arch, arch)
+	}
+	return b, nil
+}
+
+// useBundle points every installer at the bundle instead of the network.
+func useBundle(orchestrator *installer.InstallationOrchestrator, b *bundle.Bundle) {
+	orchestrator.APTInstaller.Packages = b
+	orchestrator.DownloadInstaller.Downloader = b
+	orchestrator.StepInstaller.Downloader = b
+	orchestrator.GitInstaller.Repos = b
+	if orchestrator.ScriptInstaller.Env == nil {
+		orchestrator.ScriptInstaller.Env = make(map[string]string)
+	}
+	orchestrator.ScriptInstaller.Env[bundleDirEnvVar] = b.Dir
+}
+
+// bundleDir returns the directory of the bundle orchestrator installs from,
+// or an empty string when it uses the network.
+func bundleDir(orchestrator *installer.InstallationOrchestrator) string {
+	if orchestrator.DownloadInstaller == nil {
+		return ""
+	}
+	if b, ok := orchestrator.DownloadInstaller.Downloader.(*bundle.Bundle); ok {
+		return b.Dir
+	}
+	return ""
+}
+
+func bundleSelections(b *bundle.Bundle) tui.Selections {
+	return tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{
+			{Category: bundleCategory, Tools: b.Manifest.Tools},
+		},
+	}
+}
+
+func displayBundleSummary(dir string, manifest bundle.Manifest) {
+	fmt.Printf("Bundle written to %s\n", dir)
+	fmt.Printf("Tools: %d\n", len(manifest.Tools))
+	fmt.Printf("Artifacts: %d\n", len(manifest.Artifacts))
+
+	for _, warning := range manifest.Warnings {
+		fmt.Printf("%s %s\n", pendingIcon, warning)
+	}
+
+	fmt.Printf("Install on the target machine with: devenv install --from-bundle %s\n", dir)
+}
+
+func init() {
+	bundleCreateCmd.Flags().BoolVar(&noCache, "no-cache", false, noCacheFlagUsage)
+	bundleCmd.AddCommand(bundleCreateCmd)
+	rootCmd.AddCommand(bundleCmd)
+}
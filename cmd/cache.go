@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/cache"
+	"github.com/petersenjoern/devenv/internal/download"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cacheHeader       = "Size       Last Used         URL\n"
+	cacheSeparator    = "--------------------------------------------------------------\n"
+	cacheSizeWidth    = 10
+	cacheTimeFormat   = "2006-01-02 15:04"
+	defaultPruneAge   = 30 * 24 * time.Hour
+	noCacheFlagUsage  = "Always download artifacts instead of reusing the download cache"
+	cacheEmptyMessage = "Download cache is empty"
+)
+
+var (
+	noCache       bool
+	pruneOlderAge time.Duration
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clean the download cache",
+	Long: `Downloaded artifacts are cached under $XDG_CACHE_HOME/devenv
+(~/.cache/devenv by default), keyed by URL and checksum, and reused by
+later installs and bundles. Use --no-cache on install to bypass it.
+
+Only downloads with a checksum or a release version in their URL are cached;
+others, such as "latest" assets, are fetched every time. Install scripts
+download on their own and bypass the cache.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached downloads",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		entries, err := c.List()
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(FormatCacheEntries(entries))
+		return nil
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached downloads that have not been used recently",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		removed, freed, err := c.Prune(pruneOlderAge)
+		if err != nil {
+			return fmt.Errorf("pruning cache: %w", err)
+		}
+
+		fmt.Printf("Removed %d entries, freed %s\n", removed, formatBytes(freed))
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every cached download",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := openCache()
+		if err != nil {
+			return err
+		}
+
+		if err := c.Clear(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Cleared download cache at %s\n", c.Dir)
+		return nil
+	},
+}
+
+func openCache() (*cache.Cache, error) {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, fmt.Errorf("locating cache: %w", err)
+	}
+	return cache.New(dir), nil
+}
+
+// newDownloader returns the downloader used by download-based installers and
+// bundles: cached unless --no-cache is given or the cache is unavailable.
+func newDownloader() download.Downloader {
+	httpDownloader := download.NewHTTPDownloader()
+	if noCache {
+		return httpDownloader
+	}
+
+	c, err := openCache()
+	if err != nil {
+		return httpDownloader
+	}
+
+	return &cache.Downloader{Cache: c, Downloader: httpDownloader}
+}
+
+func FormatCacheEntries(entries []cache.Entry) string {
+	if len(entries) == 0 {
+		return cacheEmptyMessage + "\n"
+	}
+
+	var output strings.Builder
+	output.WriteString(cacheHeader)
+	output.WriteString(cacheSeparator)
+
+	var total int64
+	for _, entry := range entries {
+		output.WriteString(fmt.Sprintf("%-*s %-17s %s\n",
+			cacheSizeWidth, formatBytes(entry.Size),
+			entry.LastUsed.Local().Format(cacheTimeFormat),
+			entry.URL))
+		total += entry.Size
+	}
+
+	output.WriteString(fmt.Sprintf("\n%d entries, %s\n", len(entries), formatBytes(total)))
+	return output.String()
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	cachePruneCmd.Flags().DurationVar(&pruneOlderAge, "older-than", defaultPruneAge,
+		"Remove entries not used within this duration")
+
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/cache"
+)
+
+func TestCacheCommand_ShouldFormatEntries(t *testing.T) {
+	entries := []cache.Entry{
+		{
+			URL:      "https://github.com/neovim/neovim/releases/download/stable/nvim-linux-x86_64.tar.gz",
+			Size:     12 * 1024 * 1024,
+			LastUsed: time.Now(),
+		},
+	}
+
+	output := FormatCacheEntries(entries)
+
+	if !strings.Contains(output, "nvim-linux-x86_64.tar.gz") {
+		t.Errorf("Expected output to contain cached URL, got: %s", output)
+	}
+
+	if !strings.Contains(output, "12.0 MiB") {
+		t.Errorf("Expected output to contain human readable size, got: %s", output)
+	}
+}
+
+func TestCacheCommand_ShouldReportEmptyCache(t *testing.T) {
+	output := FormatCacheEntries(nil)
+
+	if !strings.Contains(output, "empty") {
+		t.Errorf("Expected empty cache message, got: %s", output)
+	}
+}
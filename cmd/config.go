@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	configEnvVar  = "DEVENV_CONFIG"
+	userConfigDir = "devenv"
+)
+
+// systemConfigPath is the machine-wide config, tried after the user config.
+var systemConfigPath = "/etc/devenv/config.yaml"
+
+// configFile is set by --config and takes precedence over every other source.
+var configFile string
+
+// embeddedConfigLabel names the embedded catalog where a path is shown.
+const embeddedConfigLabel = "embedded catalog"
+
+// configLocation is the config file devenv uses and why it was chosen.
+// Embedded locations have no path until the catalog is extracted.
+type configLocation struct {
+	Path     string
+	Reason   string
+	Embedded bool
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration devenv uses",
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show which config file is used and why",
+	Long: `Show the config file devenv uses. The first match wins:
+  1. --config
+  2. $DEVENV_CONFIG
+  3. ./config.yaml and ../config.yaml
+  4. $XDG_CONFIG_HOME/devenv/config.yaml (~/.config/devenv by default)
+  5. /etc/devenv/config.yaml
+  6. the catalog embedded in the binary`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		location, err := locateConfig()
+		if err != nil {
+			return err
+		}
+		path := location.Path
+		if location.Embedded {
+			path = embeddedConfigLabel
+		}
+		fmt.Printf("%s (%s)\n", path, location.Reason)
+		return nil
+	},
+}
+
+// findConfigPath returns the path of the config to load, extracting the
+// embedded catalog when no config file is found.
+func findConfigPath() (string, error) {
+	location, err := locateConfig()
+	if err != nil {
+		return "", err
+	}
+	if location.Embedded {
+		if location.Path, err = defaultAssets.Resolve(embeddedConfigName); err != nil {
+			return "", configError(err)
+		}
+	}
+	logger.Debug("using config", "path", location.Path, "reason", location.Reason)
+	return location.Path, nil
+}
+
+// locateConfig finds the config file in order of precedence. A file named
+// explicitly by --config or DEVENV_CONFIG must exist; the search does not fall
+// through to other locations when it does not.
+func locateConfig() (configLocation, error) {
+	if configFile != "" {
+		return explicitConfig(configFile, "--config flag")
+	}
+	if path := os.Getenv(configEnvVar); path != "" {
+		return explicitConfig(path, configEnvVar+" environment variable")
+	}
+
+	candidates := make([]configLocation, 0, len(defaultConfigsPaths)+2)
+	for _, path := range defaultConfigsPaths {
+		candidates = append(candidates, configLocation{Path: path, Reason: "found relative to the working directory"})
+	}
+	if dir, err := userConfigHome(); err == nil {
+		candidates = append(candidates, configLocation{
+			Path:   filepath.Join(dir, userConfigDir, embeddedConfigName),
+			Reason: "user config",
+		})
+	}
+	candidates = append(candidates, configLocation{Path: systemConfigPath, Reason: "system config"})
+
+	tried := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate.Path); err == nil {
+			return candidate, nil
+		}
+		tried = append(tried, candidate.Path)
+	}
+
+	if defaultAssets.HasEmbedded(embeddedConfigName) {
+		return configLocation{Reason: "no config file found", Embedded: true}, nil
+	}
+
+	return configLocation{}, configError(fmt.Errorf("config file not found, tried: %v", tried))
+}
+
+func explicitConfig(path, reason string) (configLocation, error) {
+	if _, err := os.Stat(path); err != nil {
+		return configLocation{}, configError(fmt.Errorf("config file from %s: %w", reason, err))
+	}
+	return configLocation{Path: path, Reason: reason}, nil
+}
+
+// userConfigHome returns $XDG_CONFIG_HOME, falling back to ~/.config.
+func userConfigHome() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return xdg, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "",
+		"Config file to use instead of searching the default locations")
+	configCmd.AddCommand(configPathCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/petersenjoern/devenv/internal/assets"
+)
+
+// isolateConfigSearch points every config location at an empty temporary
+// directory so the repository's own config.yaml is not picked up.
+func isolateConfigSearch(t *testing.T) string {
+	dir := t.TempDir()
+	previousPaths, previousSystem, previousFile := defaultConfigsPaths, systemConfigPath, configFile
+	t.Cleanup(func() {
+		defaultConfigsPaths, systemConfigPath, configFile = previousPaths, previousSystem, previousFile
+	})
+
+	defaultConfigsPaths = []string{filepath.Join(dir, "config.yaml")}
+	systemConfigPath = filepath.Join(dir, "etc", "config.yaml")
+	configFile = ""
+	t.Setenv(configEnvVar, "")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	return dir
+}
+
+func writeConfigFile(t *testing.T, path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("categories: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocateConfig_ShouldFollowPrecedence(t *testing.T) {
+	// Test that --config beats DEVENV_CONFIG, which beats the XDG and system configs
+	dir := isolateConfigSearch(t)
+
+	system := systemConfigPath
+	writeConfigFile(t, system)
+	assertConfigLocation(t, system, "system config")
+
+	user := filepath.Join(dir, "xdg", "devenv", "config.yaml")
+	writeConfigFile(t, user)
+	assertConfigLocation(t, user, "user config")
+
+	fromEnv := filepath.Join(dir, "env.yaml")
+	writeConfigFile(t, fromEnv)
+	t.Setenv(configEnvVar, fromEnv)
+	assertConfigLocation(t, fromEnv, "DEVENV_CONFIG environment variable")
+
+	fromFlag := filepath.Join(dir, "flag.yaml")
+	writeConfigFile(t, fromFlag)
+	configFile = fromFlag
+	assertConfigLocation(t, fromFlag, "--config flag")
+}
+
+func TestLocateConfig_ShouldRejectMissingExplicitConfig(t *testing.T) {
+	// Test that a missing --config file is a config error instead of falling back
+	dir := isolateConfigSearch(t)
+	writeConfigFile(t, systemConfigPath)
+	configFile = filepath.Join(dir, "missing.yaml")
+
+	_, err := locateConfig()
+	if err == nil {
+		t.Fatal("Expected error for missing --config file")
+	}
+	if !errors.Is(err, os.ErrNotExist) || ExitCode(err) != ExitConfigError {
+		t.Errorf("Expected not-exist config error, got: %v (exit code %d)", err, ExitCode(err))
+	}
+}
+
+func TestLocateConfig_ShouldReportEmbeddedCatalogWithoutExtracting(t *testing.T) {
+	// Test that the embedded catalog has no path until a command loads it
+	isolateConfigSearch(t)
+	previous := defaultAssets
+	t.Cleanup(func() { defaultAssets = previous })
+	defaultAssets = assets.New(fstest.MapFS{embeddedConfigName: {Data: []byte("categories: {}\n")}})
+	t.Cleanup(func() { defaultAssets.Cleanup() })
+
+	location, err := locateConfig()
+	if err != nil {
+		t.Fatalf("Expected embedded catalog to be found, got: %v", err)
+	}
+	if !location.Embedded || location.Path != "" {
+		t.Errorf("Expected an embedded location without a path, got %+v", location)
+	}
+
+	path, err := findConfigPath()
+	if err != nil {
+		t.Fatalf("Expected embedded catalog to be extracted for loading, got: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected extracted catalog at %s, got: %v", path, err)
+	}
+}
+
+func assertConfigLocation(t *testing.T, wantPath, wantReason string) {
+	t.Helper()
+	location, err := locateConfig()
+	if err != nil {
+		t.Fatalf("Expected config to be found, got: %v", err)
+	}
+	if location.Path != wantPath || location.Reason != wantReason {
+		t.Errorf("Expected %s (%s), got %s (%s)", wantPath, wantReason, location.Path, location.Reason)
+	}
+}
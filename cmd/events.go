@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/petersenjoern/devenv/internal/installer"
+)
+
+const (
+	eventsFormatJSONL = "jsonl"
+	toolStartedMsg    = "Installing %s (%s)...\n"
+)
+
+var (
+	eventsFormat string
+	eventsFile   string
+)
+
+// eventSink receives the events of install runs. It defaults to the text
+// output; --events adds a JSON lines stream, which replaces the text output
+// when written to standard output.
+var eventSink installer.EventSink = textSink{}
+
+// eventsOutput is the --events-file being written, closed on exit.
+var eventsOutput *os.File
+
+// textOutput receives the notices printed around an install run. It is
+// standard error while the JSON lines stream owns standard output.
+var textOutput io.Writer = os.Stdout
+
+// textSink renders events as the human-readable install output.
+type textSink struct{}
+
+func (textSink) Emit(event installer.Event) {
+	switch event.Type {
+	case installer.EventToolStarted:
+		fmt.Printf(toolStartedMsg, event.DisplayName, event.Tool)
+	case installer.EventMessage:
+		fmt.Println(event.Line)
+	case installer.EventRunFinished:
+		displayInstallationResults(event.Results)
+	}
+}
+
+// openEventSinks sets up the sinks requested with --events and
+// --events-file.
+func openEventSinks() error {
+	if eventsFormat == "" {
+		if eventsFile != "" {
+			return fmt.Errorf("--events-file requires --events %s", eventsFormatJSONL)
+		}
+		return nil
+	}
+	if eventsFormat != eventsFormatJSONL {
+		return fmt.Errorf("unknown events format: %s (use %s)", eventsFormat, eventsFormatJSONL)
+	}
+
+	if eventsFile == "" {
+		eventSink = installer.NewJSONLSink(os.Stdout)
+		textOutput = os.Stderr
+		return nil
+	}
+
+	file, err := os.Create(eventsFile)
+	if err != nil {
+		return fmt.Errorf("failed to open events file: %w", err)
+	}
+	eventsOutput = file
+	eventSink = installer.EventSinks{textSink{}, installer.NewJSONLSink(file)}
+	return nil
+}
+
+func closeEventSinks() {
+	if eventsOutput != nil {
+		eventsOutput.Close()
+		eventsOutput = nil
+	}
+}
+
+func init() {
+	installCmd.Flags().StringVar(&eventsFormat, "events", "",
+		"Stream install events as jsonl to standard output (replacing the text output) or --events-file")
+	installCmd.Flags().StringVar(&eventsFile, "events-file", "",
+		"File to stream --events to, keeping the text output on the terminal")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/tui"
+)
+
+func TestEventsJSONL_ShouldKeepStandardOutputParseable(t *testing.T) {
+	// Test that with --events jsonl on standard output every line written there is JSON
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Cleanup(func() {
+		eventsFormat, reportFormat, reportFile = "", "", ""
+		eventSink, textOutput = textSink{}, os.Stdout
+	})
+
+	configPath := filepath.Join(dir, "config.yaml")
+	catalog := `categories:
+  utilities:
+    handbook:
+      display_name: "Handbook"
+      binary_name: "devenv-test-handbook"
+      install_method: "manual"
+      wsl_notes: "Download it from the vendor"
+`
+	if err := os.WriteFile(configPath, []byte(catalog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	eventsFormat = eventsFormatJSONL
+	reportFormat, reportFile = "json", filepath.Join(dir, "report.json")
+	var output strings.Builder
+	capture := captureOutput(&output)
+	if err := openEventSinks(); err != nil {
+		capture.restore()
+		t.Fatal(err)
+	}
+	selections := tui.Selections{CategoryAndTools: []tui.CategoryAndTools{{Category: "utilities", Tools: []string{"handbook"}}}}
+	orchestrator := CreateInstallationOrchestrator()
+	orchestrator.ManualInstaller.Prompter = nil // Never wait for confirmation in tests
+	results, err := executeInstallationsWith(orchestrator, selections, configPath)
+	if err == nil {
+		err = finishInstall(results)
+	}
+	capture.restore()
+	if err != nil {
+		t.Fatalf("Expected install run to succeed, got: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(output.String(), "\n"), "\n")
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("Expected only JSON on standard output, got line %q", line)
+		}
+	}
+	if !strings.Contains(output.String(), "Download it from the vendor") {
+		t.Errorf("Expected manual instructions as message events, got: %s", output.String())
+	}
+}
+
+func TestEventsJSONL_ShouldDrawSelectionFormOnStandardError(t *testing.T) {
+	// Test that the selection form stays off standard output while it carries events
+	t.Cleanup(func() {
+		eventsFormat = ""
+		eventSink, textOutput = textSink{}, os.Stdout
+	})
+
+	eventsFormat = eventsFormatJSONL
+	if err := openEventSinks(); err != nil {
+		t.Fatal(err)
+	}
+	tuiInstance, err := createTUIFromConfig(filepath.Join("..", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tuiInstance.Output != os.Stderr {
+		t.Errorf("Expected the selection form on standard error, got %v", tuiInstance.Output)
+	}
+}
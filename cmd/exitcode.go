@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"github.com/petersenjoern/devenv/internal/installer"
+)
+
+// Exit codes of devenv. Scripts and CI can rely on them:
+//
+//	0   success (tools waiting on manual steps or unsupported here included)
+//	1   any other error, e.g. invalid flags or another run holding the lock
+//	2   the configuration could not be found or is invalid
+//	3   partial failure: some tools failed, others were installed
+//	4   total failure: no tool was installed and at least one failed
+//	130 the run was cancelled
+const (
+	ExitOK             = 0
+	ExitFailure        = 1
+	ExitConfigError    = 2
+	ExitPartialFailure = 3
+	ExitTotalFailure   = 4
+	ExitCancelled      = 130
+)
+
+// ExitError is an error with the exit code devenv terminates with.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for an error returned by Execute.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitFailure
+}
+
+// configError marks err as a problem with the configuration.
+func configError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: ExitConfigError, Err: err}
+}
+
+// installFlowError wraps an error of the selection prompts; aborting them
+// with Ctrl-C cancels the run.
+func installFlowError(err error) error {
+	if errors.Is(err, huh.ErrUserAborted) {
+		return &ExitError{Code: ExitCancelled, Err: fmt.Errorf("%w: selection aborted", installer.ErrCancelled)}
+	}
+	return fmt.Errorf("running install flow: %w", err)
+}
+
+// resultsError returns the error matching the outcome of a run: nil when no
+// tool failed, otherwise a cancelled, total or partial failure. done is what
+// happens to the tools, e.g. "installed", and is used in the messages.
+func resultsError(results map[string]installer.InstallationResult, done string) error {
+	var succeeded, failed, cancelled int
+	for _, result := range results {
+		switch result.Status {
+		case installer.StatusInstalled, installer.StatusAlreadyPresent, installer.StatusRemoved:
+			succeeded++
+		case installer.StatusFailed:
+			failed++
+		case installer.StatusCancelled:
+			cancelled++
+		}
+	}
+
+	switch {
+	case cancelled > 0:
+		return &ExitError{Code: ExitCancelled, Err: fmt.Errorf("%w: %d of %d tools not %s", installer.ErrCancelled, cancelled, len(results), done)}
+	case failed == 0:
+		return nil
+	case succeeded == 0:
+		return &ExitError{Code: ExitTotalFailure, Err: fmt.Errorf("no tool could be %s: %d failed", done, failed)}
+	default:
+		return &ExitError{Code: ExitPartialFailure, Err: fmt.Errorf("%d of %d tools could not be %s", failed, len(results), done)}
+	}
+}
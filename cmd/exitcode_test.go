@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/petersenjoern/devenv/internal/installer"
+)
+
+func TestResultsError_ShouldMapOutcomeToExitCode(t *testing.T) {
+	// Test that a run's results decide between success, partial, total failure and cancellation
+	installed := installer.InstallationResult{Status: installer.StatusInstalled}
+	failed := installer.InstallationResult{Status: installer.StatusFailed}
+	manual := installer.InstallationResult{Status: installer.StatusManualPending}
+	cancelled := installer.InstallationResult{Status: installer.StatusCancelled}
+
+	tests := []struct {
+		name    string
+		results map[string]installer.InstallationResult
+		want    int
+	}{
+		{"all installed", map[string]installer.InstallationResult{"git": installed, "docs": manual}, ExitOK},
+		{"partial failure", map[string]installer.InstallationResult{"git": installed, "bat": failed}, ExitPartialFailure},
+		{"total failure", map[string]installer.InstallationResult{"bat": failed, "docs": manual}, ExitTotalFailure},
+		{"cancelled", map[string]installer.InstallationResult{"git": installed, "bat": cancelled}, ExitCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(resultsError(tt.results, "installed")); got != tt.want {
+				t.Errorf("Expected exit code %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExitCode_ShouldSeeThroughWrappedErrors(t *testing.T) {
+	// Test that config errors keep their exit code when wrapped and other errors exit 1
+	wrapped := fmt.Errorf("running install flow: %w", configError(errors.New("config file not found")))
+	if got := ExitCode(wrapped); got != ExitConfigError {
+		t.Errorf("Expected exit code %d for config error, got %d", ExitConfigError, got)
+	}
+
+	if got := ExitCode(errors.New("boom")); got != ExitFailure {
+		t.Errorf("Expected exit code %d for other errors, got %d", ExitFailure, got)
+	}
+
+	removed := installer.InstallationResult{Status: installer.StatusRemoved}
+	failed := installer.InstallationResult{Status: installer.StatusFailed}
+	uninstall := resultsError(map[string]installer.InstallationResult{"git": removed, "bat": failed}, "uninstalled")
+	if uninstall == nil || uninstall.Error() != "1 of 2 tools could not be uninstalled" {
+		t.Errorf("Expected uninstall wording, got: %v", uninstall)
+	}
+
+	aborted := installFlowError(fmt.Errorf("failed to run interactive form: %w", huh.ErrUserAborted))
+	if got := ExitCode(aborted); got != ExitCancelled {
+		t.Errorf("Expected exit code %d for an aborted selection, got %d", ExitCancelled, got)
+	}
+
+	cancelled := resultsError(map[string]installer.InstallationResult{"git": {Status: installer.StatusCancelled}}, "installed")
+	if !errors.Is(cancelled, installer.ErrCancelled) {
+		t.Errorf("Expected cancelled error to wrap ErrCancelled, got: %v", cancelled)
+	}
+}
@@ -1,11 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
-
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/assets"
 	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/detector"
 	"github.com/petersenjoern/devenv/internal/installer"
+	"github.com/petersenjoern/devenv/internal/preflight"
+	"github.com/petersenjoern/devenv/internal/state"
 	"github.com/petersenjoern/devenv/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -15,22 +29,78 @@ const (
 	summaryHeader = "\n=== Summary ==="
 	successIcon   = "✓"
 	failureIcon   = "✗"
+	pendingIcon   = "⚠"
+	skippedIcon   = "-"
 	successMsg    = "All installations completed successfully!"
 	failureMsg    = "Some installations failed. You can:"
+	pendingMsg    = "Some tools need manual installation steps:"
 	statusCmdStr  = "devenv status"
 	retryCmd      = "devenv install"
+	dryRunHeader  = "=== Install plan (dry run) ==="
+
+	// outputExcerptLines is how much of a failed install's output is shown.
+	outputExcerptLines = 10
 )
 
 var defaultConfigsPaths = []string{"./config.yaml", "../config.yaml"}
 
+const (
+	embeddedConfigName = "config.yaml"
+	archEnvVar         = "DEVENV_ARCH"
+	prefixEnvVar       = "DEVENV_PREFIX"
+	systemPrefix       = "/usr/local"
+	sudoShimPattern    = "devenv-sudo-"
+	userPathWarning    = "Warning: %s is not on your PATH; add it to use tools installed with --user\n"
+)
+
+var userMode bool
+
+// dryRun shows the install plan instead of installing it.
+var dryRun bool
+
+// applyConfigs enables writing config templates over the user's dotfiles;
+// existing files are backed up first.
+var applyConfigs bool
+
+// retries is the default number of retries for failed installs; tools can
+// override it with their own retries setting unless --retries is given.
+var retries int
+
+// scriptLibrary is the helper library sourced into install scripts. Loading
+// a config points it at the copy next to that config.
+var scriptLibrary = "install_scripts/lib/devenv.sh"
+
+// sudoShimDir holds the pass-through sudo used when running as root on a
+// system without sudo; it is created on first use and removed on exit.
+var sudoShimDir string
+
+// defaultAssets provides the catalog, scripts and templates embedded in the
+// binary; files on disk always take precedence.
+var defaultAssets *assets.Source
+
+// SetAssets registers the embedded assets used when files are missing on disk.
+func SetAssets(source *assets.Source) {
+	defaultAssets = source
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "devenv",
 	Short: "DevEnv - Automated developer environment setup",
 	Long: `DevEnv is a comprehensive tool for automating the setup of
 development environments. It provides an interactive interface to
 install and configure various development tools, programming languages,
-and essential utilities needed for software development.`,
-	Version: "0.1.0",
+and essential utilities needed for software development.
+
+Exit codes:
+  0    success
+  1    any other error
+  2    the configuration could not be found or is invalid
+  3    some tools failed, others were installed
+  4    no tool was installed and at least one failed
+  130  the run was cancelled`,
+	Version:       "0.1.0",
+	SilenceErrors: true,
+	SilenceUsage:  true,
 	CompletionOptions: cobra.CompletionOptions{
 		DisableDefaultCmd: true,
 	},
@@ -42,30 +112,96 @@ var installCmd = &cobra.Command{
 	Long: `Launch interactive TUI for tool selection and installation
 First prompts for environment selection (WSL/Linux), 
 then displays categorized tool selection with dependency resolution.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkReportFlags(); err != nil {
+			return err
+		}
+		if dryRun && (fromBundle != "" || resumeInstall) {
+			return errors.New("--dry-run cannot be combined with --from-bundle or --resume")
+		}
+		if err := openEventSinks(); err != nil {
+			return err
+		}
+		defer closeEventSinks()
+
+		if fromBundle != "" {
+			return runBundleInstall(fromBundle)
+		}
+
+		if resumeInstall {
+			return runResumeInstall()
+		}
+
 		selections, err := RunInstallFlow()
 		if err != nil {
-			fmt.Printf("Error running install flow: %v\n", err)
-			return
+			return installFlowError(err)
 		}
 
 		configPath, err := findConfigPath()
 		if err != nil {
-			fmt.Printf("Error finding config: %v\n", err)
-			return
+			return err
+		}
+
+		if dryRun {
+			return runDryRun(selections, configPath)
 		}
 
 		results, err := ExecuteInstallations(selections, configPath)
 		if err != nil {
-			fmt.Printf("Error executing installations: %v\n", err)
-			return
+			return fmt.Errorf("executing installations: %w", err)
 		}
 
-		displayInstallationResults(results)
+		return finishInstall(results)
 	},
 }
 
+// runDryRun lists the tools an install would handle, in order, with the
+// steps of declarative pipelines, without changing the system.
+func runDryRun(selections tui.Selections, configPath string) error {
+	toolConfigs, err := LoadToolConfigurations(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load tool configurations: %w", err)
+	}
+
+	orchestrator := CreateInstallationOrchestrator()
+	orchestrator.StepInstaller.DryRun = true
+
+	fmt.Println(dryRunHeader)
+	for i, toolName := range orchestrator.Plan(selections, toolConfigs) {
+		tool := toolConfigs[toolName]
+		methods := config.Methods(tool)
+		fmt.Printf("%d. %s (%s) via %s\n", i+1, tool.DisplayName, toolName, strings.Join(methods, ", falling back to "))
+		if !slices.Contains(methods, "steps") {
+			continue
+		}
+
+		steps, err := orchestrator.StepInstaller.Run(tool)
+		for _, step := range steps {
+			if step.Status == installer.StepPlanned {
+				fmt.Printf("    - %s\n", step.Description)
+			}
+		}
+		if err != nil {
+			fmt.Printf("    %s %v\n", failureIcon, err)
+		}
+	}
+	return nil
+}
+
+// finishInstall writes the requested report and returns the error matching
+// the outcome of the run.
+func finishInstall(results map[string]installer.InstallationResult) error {
+	reportErr := writeReport(results)
+	if userMode {
+		warnIfUserBinNotOnPath()
+	}
+	return errors.Join(resultsError(results, "installed"), reportErr)
+}
+
 func Execute() error {
+	defer defaultAssets.Cleanup()
+	defer removeSudoShim()
+	defer closeLogFile()
 	return rootCmd.Execute()
 }
 
@@ -119,29 +255,351 @@ func RunInstallFlowWithConfig(configPath string) (InstallResult, error) {
 }
 
 func createTUIFromConfig(configPath string) (*tui.TUI, error) {
-	cfg, err := config.LoadConfig(configPath)
+	cfg, err := loadConfig(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config from %s: %w", configPath, err)
 	}
 
-	return tui.New(cfg), nil
+	tuiInstance := tui.New(cfg)
+	tuiInstance.Output = textOutput // Off standard output while it carries events
+	return tuiInstance, nil
 }
 
 func ExecuteInstallations(selections tui.Selections, configPath string) (map[string]installer.InstallationResult, error) {
+	return executeInstallationsWith(CreateInstallationOrchestrator(), selections, configPath)
+}
+
+func executeInstallationsWith(orchestrator *installer.InstallationOrchestrator, selections tui.Selections, configPath string) (map[string]installer.InstallationResult, error) {
 	toolConfigs, err := LoadToolConfigurations(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load tool configurations: %w", err)
+		return abortRun(orchestrator.Plan(selections, nil), nil, fmt.Errorf("failed to load tool configurations: %w", err))
+	}
+	if orchestrator.ScriptInstaller != nil {
+		orchestrator.ScriptInstaller.Library = scriptLibrary // The library next to the config just loaded
 	}
 
-	orchestrator := CreateInstallationOrchestrator()
+	plan := orchestrator.Plan(selections, toolConfigs)
+	if len(plan) > 0 {
+		lock, err := acquireRunLock()
+		if err != nil {
+			return abortRun(plan, toolConfigs, err)
+		}
+		defer lock.Release()
+	}
+
+	// Sudo first: preflight needs the cached credentials to check the
+	// root-only package locks.
+	stopSudo, err := startSudo(plan, toolConfigs)
+	if err != nil {
+		return abortRun(plan, toolConfigs, err)
+	}
+	defer stopSudo()
+
+	if err := newPreflightChecker(orchestrator.Arch).Run(plan, toolConfigs); err != nil {
+		return abortRun(plan, toolConfigs, err)
+	}
+
+	// An empty run keeps the previous journal and checkpoint.
+	var checkpoint *state.Checkpoint
+	if len(plan) > 0 {
+		if checkpoint, err = startRunState(orchestrator, configPath, plan); err != nil {
+			return abortRun(plan, toolConfigs, err)
+		}
+	}
+
+	interrupted, stopNotify := notifyInterrupt()
+	defer stopNotify()
+	orchestrator.Cancelled = interrupted
 
 	results := orchestrator.ExecuteInstallations(selections, toolConfigs)
 
+	if checkpoint != nil && len(checkpoint.Remaining()) == 0 {
+		if err := checkpoint.Clear(); err != nil {
+			fmt.Fprintf(textOutput, "Warning: %v\n", err)
+		}
+	}
+
+	return results, nil
+}
+
+// acquireRunLock keeps concurrent devenv runs from racing on apt and config
+// files; install, uninstall, upgrade and rollback hold it while they change
+// the system.
+func acquireRunLock() (*state.Lock, error) {
+	dir, err := state.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return state.AcquireLock(dir)
+}
+
+// startRunState journals the files this run writes so `devenv rollback` can
+// revert them, and checkpoints progress so `devenv install --resume` can
+// continue an interrupted run. A resumed run keeps extending the journal of
+// the interrupted one.
+func startRunState(orchestrator *installer.InstallationOrchestrator, configPath string, plan []string) (*state.Checkpoint, error) {
+	dir, err := state.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, resuming := orchestrator.Progress.(*state.Checkpoint)
+
+	var journal *state.Journal
+	if resuming {
+		journal, err = state.ResumeJournal(dir)
+	} else {
+		journal, err = state.StartJournal(dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start install journal: %w", err)
+	}
+
+	if orchestrator.DownloadInstaller != nil {
+		orchestrator.DownloadInstaller.Journal = journal
+	}
+	if orchestrator.StepInstaller != nil {
+		orchestrator.StepInstaller.Journal = journal
+	}
+	if orchestrator.ConfigInstaller != nil {
+		orchestrator.ConfigInstaller.Journal = journal
+	}
+	if err := trackInstalledMethods(orchestrator); err != nil {
+		return nil, err
+	}
+
+	if !resuming {
+		if absolute, err := filepath.Abs(configPath); err == nil {
+			configPath = absolute
+		}
+		if checkpoint, err = state.StartCheckpoint(dir, configPath, bundleDir(orchestrator), userMode, plan); err != nil {
+			return nil, fmt.Errorf("failed to checkpoint install plan: %w", err)
+		}
+		orchestrator.Progress = checkpoint
+	}
+
+	return checkpoint, nil
+}
+
+// trackInstalledMethods lets orchestrator record which method installed each
+// tool, and uninstall and upgrade with that method.
+func trackInstalledMethods(orchestrator *installer.InstallationOrchestrator) error {
+	dir, err := state.DefaultDir()
+	if err != nil {
+		return err
+	}
+
+	methods, err := state.LoadMethods(dir)
+	if err != nil {
+		return err
+	}
+	orchestrator.Methods = methods
+	return nil
+}
+
+// byInstallMode runs action separately on the tools installed with --user
+// and on the others, each with an orchestrator for that mode, so uninstall
+// and upgrade act on the copy an install put in place. With --user every
+// tool is treated as a user install.
+func byInstallMode(toolNames []string, toolConfigs map[string]config.ToolConfig, action func(*installer.InstallationOrchestrator, []string) map[string]installer.InstallationResult) (map[string]installer.InstallationResult, error) {
+	dir, err := state.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	methods, err := state.LoadMethods(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var systemTools, userTools []string
+	for _, toolName := range toolNames {
+		if userMode || methods.InstalledInUserMode(toolName) {
+			userTools = append(userTools, toolName)
+		} else {
+			systemTools = append(systemTools, toolName)
+		}
+	}
+
+	defer func(previous bool) { userMode = previous }(userMode)
+	results := make(map[string]installer.InstallationResult, len(toolNames))
+	for _, group := range []struct {
+		user  bool
+		tools []string
+	}{{false, systemTools}, {true, userTools}} {
+		if len(group.tools) == 0 {
+			continue
+		}
+		userMode = group.user
+
+		stopSudo, err := startSudo(group.tools, toolConfigs)
+		if err != nil {
+			return nil, err
+		}
+		orchestrator := CreateInstallationOrchestrator()
+		orchestrator.Methods = methods
+		maps.Copy(results, action(orchestrator, group.tools))
+		stopSudo()
+	}
 	return results, nil
 }
 
+// installPrefix is where scripts and downloads install to: /usr/local, or
+// ~/.local in --user mode.
+func installPrefix(det *detector.Detector) string {
+	if userMode {
+		if userPrefix, err := det.UserPrefix(); err == nil {
+			return userPrefix
+		}
+	}
+	return systemPrefix
+}
+
+// newPreflightChecker checks the plan against this machine; scripts missing on
+// disk are fine as long as an embedded copy exists.
+func newPreflightChecker(arch string) *preflight.Checker {
+	checker := preflight.New()
+	checker.Arch = arch
+	checker.UserMode = userMode
+	checker.Prefix = installPrefix(detector.New())
+	checker.ScriptExists = func(path string) bool {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+		return defaultAssets.HasEmbedded(path)
+	}
+	checker.Warn = func(message string) {
+		fmt.Fprintf(textOutput, "Warning: %s\n", message)
+	}
+	return checker
+}
+
+// startSudo validates sudo credentials once before any tool is installed and
+// keeps them alive until the returned function is called. It is a no-op in
+// --user mode, as root, or when no planned tool needs root.
+func startSudo(plan []string, tools map[string]config.ToolConfig) (func(), error) {
+	noop := func() {}
+	if userMode || !requiresRoot(plan, tools) {
+		return noop, nil
+	}
+
+	privileges := installer.DetectPrivileges()
+	if privileges.Root {
+		return noop, nil
+	}
+	if err := privileges.Check(); err != nil {
+		return nil, err
+	}
+
+	keepalive := installer.NewSudoKeepalive()
+	if err := keepalive.Start(); err != nil {
+		return nil, err
+	}
+	return keepalive.Stop, nil
+}
+
+// requiresRoot reports whether any planned tool is installed with a method
+// that runs privileged commands.
+func requiresRoot(plan []string, tools map[string]config.ToolConfig) bool {
+	for _, toolName := range plan {
+		for _, method := range config.Methods(tools[toolName]) {
+			switch method {
+			case "apt", "script", "download", "steps":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newCommandExecutor returns the executor shared by all installers, adapted
+// to whether devenv runs as root and whether sudo is available. Command
+// output goes to capture and started is told about each command, if set.
+func newCommandExecutor(capture io.Writer, started func(string)) installer.CommandExecutor {
+	privileges := installer.DetectPrivileges()
+	executor := &installer.SudoExecutor{
+		CommandExecutor: &installer.RealCommandExecutor{Capture: capture, Started: started, Logger: logger},
+		Privileges:      privileges,
+	}
+
+	if privileges.Root && !privileges.HasSudo {
+		if sudoShimDir == "" {
+			dir, err := os.MkdirTemp("", sudoShimPattern)
+			if err == nil && installer.WriteSudoShim(dir) == nil {
+				sudoShimDir = dir
+			}
+		}
+		executor.ShimDir = sudoShimDir
+	}
+
+	return executor
+}
+
+// detectTool reports whether a tool is installed and its version, asking
+// install scripts that implement the check verb.
+func detectTool(det *detector.Detector, scripts *installer.ScriptInstaller) func(config.ToolConfig) (bool, string) {
+	det.Scripts = scripts
+	return func(tool config.ToolConfig) (bool, string) {
+		status := det.DetectTool(tool)
+		return status.BinaryInstalled, status.Version
+	}
+}
+
+// notifyInterrupt records SIGINT and SIGTERM instead of exiting, so the run
+// can report the remaining tools as cancelled and release its lock.
+func notifyInterrupt() (interrupted func() bool, stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	var received atomic.Bool
+	go func() {
+		for range signals {
+			received.Store(true)
+		}
+	}()
+
+	return received.Load, func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
+func removeSudoShim() {
+	if sudoShimDir != "" {
+		os.RemoveAll(sudoShimDir)
+		sudoShimDir = ""
+	}
+}
+
+// loadConfig loads the config at configPath. Scripts and templates missing
+// next to it fall back to the copies embedded in the binary.
+func loadConfig(configPath string) (config.Config, error) {
+	logger.Debug("loading config", "path", configPath)
+
+	cfg, err := config.LoadConfigWithFallback(configPath, func(name string) bool {
+		embedded := defaultAssets.HasEmbedded(name)
+		if embedded {
+			logger.Debug("using embedded copy", "file", name)
+		}
+		return embedded
+	})
+	if err != nil {
+		logger.Error("failed to load config", "path", configPath, "error", err)
+		return cfg, configError(err)
+	}
+	if cfg.ScriptLibrary != "" {
+		scriptLibrary = cfg.ScriptLibrary
+	}
+
+	tools := 0
+	for _, category := range cfg.Categories {
+		tools += len(category)
+	}
+	logger.Info("loaded config", "path", configPath, "categories", len(cfg.Categories), "tools", tools)
+	return cfg, nil
+}
+
 func LoadToolConfigurations(configPath string) (map[string]config.ToolConfig, error) {
-	cfg, err := config.LoadConfig(configPath)
+	cfg, err := loadConfig(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
@@ -157,67 +615,257 @@ func LoadToolConfigurations(configPath string) (map[string]config.ToolConfig, er
 }
 
 func CreateInstallationOrchestrator() *installer.InstallationOrchestrator {
-	return &installer.InstallationOrchestrator{
-		APTInstaller:    installer.NewAPTInstaller(),    // Real APT command execution
-		ScriptInstaller: installer.NewScriptInstaller(), // Real script execution
-		ManualInstaller: &installer.ManualInstaller{},   // User instruction display
+	det := detector.New()
+	det.Logger = logger
+	arch := det.DetectArchitecture()
+	logger.Debug("detected system", "arch", arch, "user_mode", userMode)
+
+	prefix := installPrefix(det)
+
+	events := &installer.EventEmitter{Sink: eventSink}
+	output := installer.NewOutputTail()
+	executor := newCommandExecutor(io.MultiWriter(output, events), events.CommandStarted) // Drops sudo when running as root
+
+	aptInstaller := installer.NewAPTInstaller() // Real APT command execution
+	aptInstaller.CommandExecutor = executor
+	aptInstaller.UserMode = userMode // No sudo in rootless mode
+	aptInstaller.Arch = arch         // Architecture for apt repositories
+
+	scriptInstaller := installer.NewScriptInstaller() // Real script execution
+	scriptInstaller.CommandExecutor = executor
+	scriptInstaller.Env = map[string]string{archEnvVar: arch, prefixEnvVar: prefix}
+	scriptInstaller.Library = scriptLibrary // Helpers sourced into every script, next to the loaded config
+	scriptInstaller.Logger = logger         // Script log records
+	if defaultAssets != nil {
+		scriptInstaller.Assets = defaultAssets // Embedded scripts when missing on disk
 	}
-}
 
-func findConfigPath() (string, error) {
+	downloadInstaller := installer.NewDownloadInstaller() // Real HTTP downloads
+	downloadInstaller.CommandExecutor = executor
+	downloadInstaller.Downloader = newDownloader() // Reused from the download cache
+	downloadInstaller.Arch = arch
+	if userMode {
+		downloadInstaller.UserPrefix = prefix // Install into ~/.local/bin
+	}
 
-	for _, configPath := range defaultConfigsPaths {
-		if _, err := os.Stat(configPath); err == nil {
-			return configPath, nil
+	gitInstaller := installer.NewGitInstaller() // Real git clones
+	gitInstaller.CommandExecutor = executor
+	gitInstaller.Env = scriptInstaller.Env
+
+	stepInstaller := installer.NewStepInstaller() // Declarative steps pipelines
+	stepInstaller.CommandExecutor = executor
+	stepInstaller.Downloader = downloadInstaller.Downloader
+	stepInstaller.APT = aptInstaller // Package steps share apt settings
+	stepInstaller.Arch = arch
+	stepInstaller.Prefix = prefix
+	stepInstaller.Env = scriptInstaller.Env
+
+	var configInstaller *installer.ConfigInstaller
+	if applyConfigs {
+		configInstaller = &installer.ConfigInstaller{} // Config templates after install
+		if defaultAssets != nil {
+			configInstaller.Assets = defaultAssets // Embedded templates when missing on disk
 		}
 	}
 
-	return "", fmt.Errorf("config file not found, tried: %v", defaultConfigsPaths)
+	manualInstaller := &installer.ManualInstaller{Logger: logger} // User instruction display
+	if isInteractiveTerminal() {
+		manualInstaller.Prompter = &tui.ManualPrompt{Output: textOutput} // Wait for the user to finish
+		manualInstaller.Detector = det                                   // Verify the manual installation
+	}
+
+	orchestrator := &installer.InstallationOrchestrator{
+		APTInstaller:      aptInstaller,
+		ScriptInstaller:   scriptInstaller,
+		ManualInstaller:   manualInstaller,
+		DownloadInstaller: downloadInstaller,
+		GitInstaller:      gitInstaller,
+		StepInstaller:     stepInstaller,
+		ConfigInstaller:   configInstaller,
+		CommandExecutor:   executor, // Post-install commands
+		Arch:              arch,
+		MethodAvailable:   newPreflightChecker(arch).MethodAvailable, // Skip methods missing their commands
+		Detect:            detectTool(det, scriptInstaller),          // Skip tools already present
+		Output:            output,                                    // Output excerpts for results
+		Events:            events,                                    // Progress and results for the sinks
+		Logger:            logger,
+		UserMode:          userMode, // Recorded so uninstall finds ~/.local installs
+		Retries:           retries,
+		RetriesOverride:   rootCmd.PersistentFlags().Changed("retries"), // --retries beats per-tool settings
+	}
+	manualInstaller.Notify = orchestrator.Notify // Instructions as message events
+	if configInstaller != nil {
+		configInstaller.Notify = orchestrator.Notify // Backups as message events
+	}
+	return orchestrator
+}
+
+// warnIfUserBinNotOnPath reminds the user that rootless installs land in
+// ~/.local/bin, which must be on PATH for the tools to be found.
+func warnIfUserBinNotOnPath() {
+	det := detector.New()
+	prefix, err := det.UserPrefix()
+	if err != nil {
+		return
+	}
+
+	binDir := filepath.Join(prefix, "bin")
+	if !det.IsDirOnPath(binDir) {
+		fmt.Fprintf(textOutput, userPathWarning, binDir)
+	}
+}
+
+// isInteractiveTerminal reports whether stdin is attached to a terminal, so
+// devenv can wait for user confirmation.
+func isInteractiveTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
 
 // displayInstallationResults shows the results of installations to the user
 func displayInstallationResults(results map[string]installer.InstallationResult) {
 	fmt.Println(resultsHeader)
 
-	successful, failed := displayToolResults(results)
-	displaySummary(len(results), successful, failed)
-	displayGuidance(successful, failed)
+	successful, failed, pending, unsupported := displayToolResults(results)
+	displaySummary(len(results), successful, failed, pending, unsupported)
+	displayGuidance(successful, failed, pending)
 }
 
-// displayToolResults shows individual tool installation results and returns counts
-func displayToolResults(results map[string]installer.InstallationResult) (successful, failed int) {
-	for toolName, result := range results {
-		if result.Success {
-			fmt.Printf("%s %s (%s) - installed successfully\n", successIcon, result.Tool.DisplayName, toolName)
+// displayToolResults shows individual tool installation results in install
+// order and returns counts
+func displayToolResults(results map[string]installer.InstallationResult) (successful, failed, pending, unsupported int) {
+	for _, toolName := range installer.InOrder(results) {
+		result := results[toolName]
+		switch result.Status {
+		case installer.StatusInstalled:
+			fmt.Printf("%s %s (%s) - installed successfully%s%s%s\n", successIcon, result.Tool.DisplayName, toolName, methodSuffix(result), attemptsSuffix(result), detailsSuffix(result))
 			successful++
-		} else {
-			fmt.Printf("%s %s (%s) - installation failed: %v\n", failureIcon, result.Tool.DisplayName, toolName, result.Error)
+		case installer.StatusAlreadyPresent:
+			fmt.Printf("%s %s (%s) - already installed%s\n", successIcon, result.Tool.DisplayName, toolName, detailsSuffix(result))
+			successful++
+		case installer.StatusManualPending:
+			fmt.Printf("%s %s (%s) - pending manual action\n", pendingIcon, result.Tool.DisplayName, toolName)
+			pending++
+		case installer.StatusSkipped:
+			fmt.Printf("%s %s (%s) - %v\n", skippedIcon, result.Tool.DisplayName, toolName, result.Error)
+			unsupported++
+		case installer.StatusCancelled:
+			fmt.Printf("%s %s (%s) - cancelled\n", skippedIcon, result.Tool.DisplayName, toolName)
+			failed++
+		default:
+			fmt.Printf("%s %s (%s) - installation failed%s: %v\n", failureIcon, result.Tool.DisplayName, toolName, attemptsSuffix(result), result.Error)
+			displaySteps(result.Steps)
+			displayOutput(result.Output)
 			failed++
 		}
 	}
-	return successful, failed
+	return successful, failed, pending, unsupported
+}
+
+// displaySteps lists the steps of a failed pipeline with their outcome.
+func displaySteps(steps []installer.StepResult) {
+	for _, step := range steps {
+		icon := skippedIcon
+		switch step.Status {
+		case installer.StepDone:
+			icon = successIcon
+		case installer.StepFailed:
+			icon = failureIcon
+		}
+		fmt.Printf("    %s %s\n", icon, step.Description)
+	}
+}
+
+// methodSuffix names the method that installed a tool with a fallback chain.
+func methodSuffix(result installer.InstallationResult) string {
+	if len(result.Tool.InstallMethods) > 0 && result.Method != "" {
+		return " via " + result.Method
+	}
+	return ""
+}
+
+// detailsSuffix shows the detected version and how long the install took.
+func detailsSuffix(result installer.InstallationResult) string {
+	var details []string
+	if result.Version != "" {
+		details = append(details, result.Version)
+	}
+	if duration := result.Duration(); duration >= time.Second {
+		details = append(details, duration.Round(100*time.Millisecond).String())
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+// displayOutput shows the last lines a failed install printed.
+func displayOutput(output string) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > outputExcerptLines {
+		lines = lines[len(lines)-outputExcerptLines:]
+	}
+	for _, line := range lines {
+		if line != "" {
+			fmt.Printf("    | %s\n", line)
+		}
+	}
+}
+
+func attemptsSuffix(result installer.InstallationResult) string {
+	if len(result.Attempts) > 1 {
+		return fmt.Sprintf(" after %d attempts", len(result.Attempts))
+	}
+	return ""
 }
 
 // displaySummary shows installation summary statistics
-func displaySummary(total, successful, failed int) {
+func displaySummary(total, successful, failed, pending, unsupported int) {
 	fmt.Printf(summaryHeader + "\n")
 	fmt.Printf("Total attempted: %d\n", total)
 	fmt.Printf("Successful: %d\n", successful)
 	fmt.Printf("Failed: %d\n", failed)
+	if pending > 0 {
+		fmt.Printf("Pending manual action: %d\n", pending)
+	}
+	if unsupported > 0 {
+		fmt.Printf("Unsupported on this system: %d\n", unsupported)
+	}
 }
 
 // displayGuidance provides next-step guidance based on installation results
-func displayGuidance(successful, failed int) {
+func displayGuidance(successful, failed, pending int) {
+	if pending > 0 {
+		fmt.Printf("\n" + pendingMsg + "\n")
+		fmt.Printf("- Follow the instructions shown above, then run '%s' to verify\n", statusCmdStr)
+	}
+
 	if failed > 0 {
 		fmt.Printf("\n" + failureMsg + "\n")
 		fmt.Printf("- Run '%s' to check current tool status\n", statusCmdStr)
 		fmt.Printf("- Re-run '%s' to retry failed installations\n", retryCmd)
-	} else if successful > 0 {
+	} else if successful > 0 && pending == 0 {
 		fmt.Printf("\n" + successMsg + "\n")
 		fmt.Printf("Run '%s' to verify your development environment.\n", statusCmdStr)
 	}
 }
 
 func init() {
+	installCmd.Flags().StringVar(&fromBundle, "from-bundle", "",
+		"Install the tools of an offline bundle using only its content")
+	installCmd.Flags().BoolVar(&resumeInstall, "resume", false,
+		"Continue the last interrupted install from the first unfinished tool")
+	installCmd.Flags().BoolVar(&noCache, "no-cache", false, noCacheFlagUsage)
+	installCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Show the install plan and the steps of step pipelines without installing anything")
+	installCmd.Flags().BoolVar(&applyConfigs, "apply-configs", false,
+		"Write the config templates of installed tools, backing up existing files")
+	installCmd.Flags().BoolVar(&userMode, "user", false,
+		"Rootless install into ~/.local without sudo; apt tools are reported as unavailable")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 0,
+		"Retry failed installs this many times with exponential backoff (when given, overrides per-tool retries)")
 	rootCmd.AddCommand(installCmd)
 }
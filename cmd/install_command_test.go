@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -48,16 +50,16 @@ func TestInstallCommand_ShouldDisplayInstallationResults(t *testing.T) {
 				DisplayName: "Git Version Control",
 				BinaryName:  "git",
 			},
-			Success: true,
-			Error:   nil,
+			Status: installer.StatusInstalled,
+			Error:  nil,
 		},
 		"nonexistent-tool": {
 			Tool: config.ToolConfig{
 				DisplayName: "Nonexistent Tool",
 				BinaryName:  "nonexistent-tool",
 			},
-			Success: false,
-			Error:   fmt.Errorf("installation failed: package not found"),
+			Status: installer.StatusFailed,
+			Error:  fmt.Errorf("installation failed: package not found"),
 		},
 	}
 
@@ -242,16 +244,16 @@ func TestInstallCommand_ShouldProvideHelpfulGuidanceOnFailures(t *testing.T) {
 				DisplayName: "Git Version Control",
 				BinaryName:  "git",
 			},
-			Success: true,
-			Error:   nil,
+			Status: installer.StatusInstalled,
+			Error:  nil,
 		},
 		"failed-tool": {
 			Tool: config.ToolConfig{
 				DisplayName: "Failed Tool",
 				BinaryName:  "failed-tool",
 			},
-			Success: false,
-			Error:   fmt.Errorf("installation failed"),
+			Status: installer.StatusFailed,
+			Error:  fmt.Errorf("installation failed"),
 		},
 	}
 
@@ -288,16 +290,16 @@ func TestInstallCommand_ShouldProvideSuccessGuidanceOnAllSuccess(t *testing.T) {
 				DisplayName: "Git Version Control",
 				BinaryName:  "git",
 			},
-			Success: true,
-			Error:   nil,
+			Status: installer.StatusInstalled,
+			Error:  nil,
 		},
 		"vim": {
 			Tool: config.ToolConfig{
 				DisplayName: "Vim Editor",
 				BinaryName:  "vim",
 			},
-			Success: true,
-			Error:   nil,
+			Status: installer.StatusInstalled,
+			Error:  nil,
 		},
 	}
 
@@ -324,3 +326,156 @@ func TestInstallCommand_ShouldProvideSuccessGuidanceOnAllSuccess(t *testing.T) {
 		t.Errorf("Should not show failure guidance on all success, got: %s", outputStr)
 	}
 }
+
+func TestInstallCommand_ShouldDisplayPendingManualActions(t *testing.T) {
+	// Test that pending manual installations are shown separately from failures
+
+	mockResults := map[string]installer.InstallationResult{
+		"alacritty": {
+			Tool: config.ToolConfig{
+				DisplayName: "Alacritty Terminal",
+				BinaryName:  "alacritty",
+			},
+			Status: installer.StatusManualPending,
+			Error:  fmt.Errorf("%w: skipped by user", installer.ErrManualActionPending),
+		},
+	}
+
+	var output strings.Builder
+	originalOutput := captureOutput(&output)
+
+	displayInstallationResults(mockResults)
+
+	originalOutput.restore()
+	outputStr := output.String()
+
+	if !strings.Contains(outputStr, "pending manual action") {
+		t.Errorf("Expected output to show pending manual action, got: %s", outputStr)
+	}
+
+	if !strings.Contains(outputStr, "Failed: 0") {
+		t.Errorf("Expected pending tools not to be counted as failed, got: %s", outputStr)
+	}
+
+	if !strings.Contains(outputStr, "Pending manual action: 1") {
+		t.Errorf("Expected summary to count pending tools, got: %s", outputStr)
+	}
+
+	if strings.Contains(outputStr, "All installations completed successfully") {
+		t.Errorf("Should not report full success while manual steps are pending, got: %s", outputStr)
+	}
+}
+
+func TestInstallCommand_ShouldDisplayResultsInInstallOrder(t *testing.T) {
+	// Test that results are listed in install order with their status
+	mockResults := map[string]installer.InstallationResult{
+		"zsh": {
+			Tool:   config.ToolConfig{DisplayName: "Zsh Shell", BinaryName: "zsh"},
+			Status: installer.StatusInstalled,
+			Order:  0,
+		},
+		"curl": {
+			Tool:    config.ToolConfig{DisplayName: "Curl", BinaryName: "curl"},
+			Status:  installer.StatusAlreadyPresent,
+			Version: "curl 8.5.0",
+			Order:   1,
+		},
+		"bat": {
+			Tool:   config.ToolConfig{DisplayName: "Bat", BinaryName: "bat"},
+			Status: installer.StatusCancelled,
+			Error:  installer.ErrCancelled,
+			Order:  2,
+		},
+	}
+
+	var output strings.Builder
+	originalOutput := captureOutput(&output)
+
+	displayInstallationResults(mockResults)
+
+	originalOutput.restore()
+	outputStr := output.String()
+
+	zsh, curl, bat := strings.Index(outputStr, "Zsh Shell"), strings.Index(outputStr, "Curl"), strings.Index(outputStr, "Bat")
+	if zsh < 0 || curl < zsh || bat < curl {
+		t.Errorf("Expected results in install order, got: %s", outputStr)
+	}
+	if !strings.Contains(outputStr, "already installed (curl 8.5.0)") {
+		t.Errorf("Expected already present tool with its version, got: %s", outputStr)
+	}
+	if !strings.Contains(outputStr, "Bat (bat) - cancelled") {
+		t.Errorf("Expected cancelled tool, got: %s", outputStr)
+	}
+}
+
+func TestRequiresRoot_ShouldIgnoreManualOnlyPlans(t *testing.T) {
+	// Test that sudo is only validated when a planned tool runs privileged commands
+	tools := map[string]config.ToolConfig{
+		"alacritty": {BinaryName: "alacritty", InstallMethod: "manual"},
+		"git":       {BinaryName: "git", InstallMethod: "apt", PackageName: "git"},
+	}
+
+	if requiresRoot([]string{"alacritty"}, tools) {
+		t.Errorf("Expected manual-only plan not to require root")
+	}
+	if !requiresRoot([]string{"alacritty", "git"}, tools) {
+		t.Errorf("Expected apt tool to require root")
+	}
+}
+
+func TestRunDryRun_ShouldListPlanAndPipelineStepsWithoutInstalling(t *testing.T) {
+	// Test that --dry-run shows the planned methods and the steps of a pipeline
+	configPath, err := findConfigPath()
+	if err != nil {
+		t.Skipf("No config file found for testing, skipping: %v", err)
+	}
+	selections := tui.Selections{CategoryAndTools: []tui.CategoryAndTools{{Category: "utilities", Tools: []string{"bat"}}}}
+
+	var output strings.Builder
+	originalOutput := captureOutput(&output)
+	err = runDryRun(selections, configPath)
+	originalOutput.restore()
+
+	if err != nil {
+		t.Fatalf("Expected dry run to succeed, got: %v", err)
+	}
+	outputStr := output.String()
+	if !strings.Contains(outputStr, "(bat) via apt, falling back to steps") {
+		t.Errorf("Expected bat with its fallback chain, got: %s", outputStr)
+	}
+	if !strings.Contains(outputStr, "- download https://github.com/sharkdp/bat/releases/download/v0.24.0/") {
+		t.Errorf("Expected the planned download step, got: %s", outputStr)
+	}
+}
+
+func TestLoadConfig_ShouldUseScriptLibraryNextToConfig(t *testing.T) {
+	// Test that scripts source the helper library next to the config, not one relative to the working directory
+	previous := scriptLibrary
+	t.Cleanup(func() { scriptLibrary = previous })
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"config.yaml":                   "categories:\n  shell:\n    zsh:\n      install_method: script\n      install_script: install_scripts/zsh.sh\n",
+		"install_scripts/zsh.sh":        "",
+		"install_scripts/lib/devenv.sh": "",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := loadConfig(filepath.Join(dir, "config.yaml")); err != nil {
+		t.Fatalf("Expected config to load, got: %v", err)
+	}
+
+	if want := filepath.Join(dir, "install_scripts", "lib", "devenv.sh"); scriptLibrary != want {
+		t.Errorf("Expected script library %s, got %s", want, scriptLibrary)
+	}
+	if library := CreateInstallationOrchestrator().ScriptInstaller.Library; library != scriptLibrary {
+		t.Errorf("Expected the script installer to use %s, got %s", scriptLibrary, library)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+var (
+	logLevel  string
+	logFormat string
+	logFile   string
+)
+
+// logger receives the debug detail of config loading, detection and
+// installation. It is configured from the global --log-* flags before any
+// command runs.
+var logger = slog.Default()
+
+// logOutput is the --log-file being written, closed on exit.
+var logOutput *os.File
+
+// setupLogging builds the logger from the --log-level, --log-format and
+// --log-file flags and makes it the default logger.
+func setupLogging() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+		return fmt.Errorf("invalid --log-level %q (use debug, info, warn or error)", logLevel)
+	}
+
+	var out io.Writer = os.Stderr
+	if logFile != "" {
+		file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		logOutput, out = file, file
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(logFormat) {
+	case logFormatText:
+		logger = slog.New(slog.NewTextHandler(out, options))
+	case logFormatJSON:
+		logger = slog.New(slog.NewJSONHandler(out, options))
+	default:
+		return fmt.Errorf("invalid --log-format %q (use %s or %s)", logFormat, logFormatText, logFormatJSON)
+	}
+
+	slog.SetDefault(logger)
+	return nil
+}
+
+func closeLogFile() {
+	if logOutput != nil {
+		logOutput.Close()
+		logOutput = nil
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn",
+		"Minimum level of log messages: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logFormatText,
+		"Format of log messages: text or json")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "",
+		"Append log messages to this file instead of standard error")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return setupLogging()
+	}
+}
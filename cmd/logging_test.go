@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func resetLogging(t *testing.T) {
+	t.Cleanup(func() {
+		closeLogFile()
+		logLevel, logFormat, logFile = "warn", logFormatText, ""
+		logger = slog.Default()
+	})
+}
+
+func TestSetupLogging_ShouldWriteJSONToLogFile(t *testing.T) {
+	// Test that --log-format json and --log-file send records at the chosen level to the file
+	resetLogging(t)
+	previous := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	logLevel, logFormat, logFile = "debug", logFormatJSON, filepath.Join(t.TempDir(), "devenv.log")
+	if err := setupLogging(); err != nil {
+		t.Fatalf("Expected logging setup to succeed, got: %v", err)
+	}
+
+	logger.Debug("loading config", "path", "config.yaml")
+	closeLogFile()
+
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(content))), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", content, err)
+	}
+	if record["level"] != "DEBUG" || record["path"] != "config.yaml" {
+		t.Errorf("Expected debug record with attributes, got %v", record)
+	}
+}
+
+func TestSetupLogging_ShouldRejectInvalidFlags(t *testing.T) {
+	// Test that unknown levels and formats are reported instead of ignored
+	resetLogging(t)
+
+	logLevel, logFormat = "verbose", logFormatText
+	if err := setupLogging(); err == nil || !strings.Contains(err.Error(), "--log-level") {
+		t.Errorf("Expected invalid level error, got: %v", err)
+	}
+
+	logLevel, logFormat = "info", "xml"
+	if err := setupLogging(); err == nil || !strings.Contains(err.Error(), "--log-format") {
+		t.Errorf("Expected invalid format error, got: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/installer"
+	"github.com/petersenjoern/devenv/internal/report"
+)
+
+const reportWrittenMsg = "Report written to %s\n"
+
+var defaultReportFiles = map[string]string{
+	report.FormatJSON:  "devenv-report.json",
+	report.FormatJUnit: "devenv-report.xml",
+}
+
+var (
+	reportFormat string
+	reportFile   string
+)
+
+// checkReportFlags rejects unknown report formats before anything is
+// installed.
+func checkReportFlags() error {
+	if reportFormat == "" {
+		if reportFile != "" {
+			return fmt.Errorf("--report-file requires --report %s|%s", report.FormatJSON, report.FormatJUnit)
+		}
+		return nil
+	}
+	return report.CheckFormat(reportFormat)
+}
+
+// writeReport writes the machine-readable report requested with --report to
+// --report-file, or devenv-report.json / devenv-report.xml.
+func writeReport(results map[string]installer.InstallationResult) error {
+	if reportFormat == "" {
+		return nil
+	}
+
+	path := reportFile
+	if path == "" {
+		path = defaultReportFiles[reportFormat]
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	defer file.Close()
+
+	if err := report.Write(file, reportFormat, results); err != nil {
+		return err
+	}
+	fmt.Fprintf(textOutput, reportWrittenMsg, path)
+	return nil
+}
+
+// abortRun reports every tool of plan as failed with err, for runs that stop
+// before installing anything, so the report still says why. It returns err.
+func abortRun(plan []string, toolConfigs map[string]config.ToolConfig, err error) (map[string]installer.InstallationResult, error) {
+	now := time.Now()
+	results := make(map[string]installer.InstallationResult, len(plan))
+	for i, toolName := range plan {
+		results[toolName] = installer.InstallationResult{
+			Tool:       toolConfigs[toolName],
+			Status:     installer.StatusFailed,
+			Error:      err,
+			Order:      i,
+			StartedAt:  now,
+			FinishedAt: now,
+		}
+	}
+
+	if reportErr := writeReport(results); reportErr != nil {
+		fmt.Fprintf(textOutput, "Warning: %v\n", reportErr)
+	}
+	return nil, err
+}
+
+func init() {
+	installCmd.Flags().StringVar(&reportFormat, "report", "",
+		"Write a machine-readable report of the results: json or junit")
+	installCmd.Flags().StringVar(&reportFile, "report-file", "",
+		"File to write the --report to (default: devenv-report.json or devenv-report.xml)")
+}
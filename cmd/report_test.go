@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/report"
+	"github.com/petersenjoern/devenv/internal/state"
+	"github.com/petersenjoern/devenv/internal/tui"
+)
+
+func TestReport_ShouldBeWrittenWhenRunStopsBeforeInstalling(t *testing.T) {
+	// Test that a run stopped by the run lock still writes a report with the reason
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Cleanup(func() { reportFormat, reportFile = "", "" })
+
+	configPath := filepath.Join(dir, "config.yaml")
+	catalog := `categories:
+  utilities:
+    handbook:
+      display_name: "Handbook"
+      binary_name: "devenv-test-handbook"
+      install_method: "manual"
+`
+	if err := os.WriteFile(configPath, []byte(catalog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stateDir, err := state.DefaultDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := state.AcquireLock(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Release()
+
+	reportFormat, reportFile = "json", filepath.Join(dir, "report.json")
+	var output strings.Builder
+	capture := captureOutput(&output)
+	selections := tui.Selections{CategoryAndTools: []tui.CategoryAndTools{{Category: "utilities", Tools: []string{"handbook"}}}}
+	_, err = executeInstallationsWith(CreateInstallationOrchestrator(), selections, configPath)
+	capture.restore()
+	if err == nil {
+		t.Fatal("Expected the run to stop at the run lock")
+	}
+
+	data, readErr := os.ReadFile(reportFile)
+	if readErr != nil {
+		t.Fatalf("Expected a report despite the early exit, got: %v", readErr)
+	}
+	var written report.Report
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if len(written.Tools) != 1 || written.Tools[0].Status != "failed" || written.Tools[0].Error != err.Error() {
+		t.Errorf("Expected handbook failed with %q, got: %+v", err, written.Tools)
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/petersenjoern/devenv/internal/state"
+	"github.com/petersenjoern/devenv/internal/tui"
+)
+
+const (
+	resumeCategory   = "resume"
+	resumeNothingMsg = "No interrupted install to resume"
+	resumeMsg        = "Resuming install: %d of %d tools already finished\n"
+)
+
+var resumeInstall bool
+
+// runResumeInstall continues the last interrupted install with the same plan,
+// configuration, mode and bundle, skipping tools that already finished.
+func runResumeInstall() error {
+	dir, err := state.DefaultDir()
+	if err != nil {
+		return fmt.Errorf("locating state directory: %w", err)
+	}
+
+	checkpoint, err := state.LoadCheckpoint(dir)
+	if errors.Is(err, state.ErrNoCheckpoint) {
+		fmt.Println(resumeNothingMsg)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	// The embedded catalog is extracted to a new location on every run.
+	configPath := checkpoint.ConfigPath
+	if _, err := os.Stat(configPath); err != nil {
+		if configPath, err = findConfigPath(); err != nil {
+			return err
+		}
+	}
+
+	userMode = userMode || checkpoint.UserMode
+	fmt.Fprintf(textOutput, resumeMsg, len(checkpoint.Plan)-len(checkpoint.Remaining()), len(checkpoint.Plan))
+
+	orchestrator := CreateInstallationOrchestrator()
+	orchestrator.Progress = checkpoint
+	if checkpoint.BundleDir != "" {
+		b, err := openBundle(checkpoint.BundleDir)
+		if err != nil {
+			return err
+		}
+		useBundle(orchestrator, b)
+	}
+
+	results, err := executeInstallationsWith(orchestrator, resumeSelections(checkpoint), configPath)
+	if err != nil {
+		return fmt.Errorf("executing installations: %w", err)
+	}
+
+	return finishInstall(results)
+}
+
+func resumeSelections(checkpoint *state.Checkpoint) tui.Selections {
+	return tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{
+			{Category: resumeCategory, Tools: checkpoint.Plan},
+		},
+	}
+}
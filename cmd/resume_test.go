@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/state"
+	"github.com/petersenjoern/devenv/internal/tui"
+)
+
+func TestResumeInstall_ShouldKeepInstallingFromBundle(t *testing.T) {
+	// Test that resuming an interrupted --from-bundle run installs from the bundle, not the network
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Cleanup(func() { userMode = false })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "notes")
+	}))
+
+	configPath := filepath.Join(dir, "config.yaml")
+	catalog := fmt.Sprintf(`categories:
+  utilities:
+    notes:
+      display_name: "Notes"
+      binary_name: "devenv-test-notes"
+      install_method: "steps"
+      steps:
+        - action: "download"
+          url: "%s/notes.txt"
+        - action: "copy"
+          src: "notes.txt"
+          dest: "{prefix}/share/devenv-test-notes"
+`, server.URL)
+	if err := os.WriteFile(configPath, []byte(catalog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	bundleDir := filepath.Join(dir, "bundle")
+	selections := tui.Selections{CategoryAndTools: []tui.CategoryAndTools{{Category: "utilities", Tools: []string{"notes"}}}}
+	if _, err := CreateBundle(bundleDir, selections, configPath); err != nil {
+		t.Fatal(err)
+	}
+	server.Close() // Offline from here on
+
+	stateDir, err := state.DefaultDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.StartCheckpoint(stateDir, configPath, bundleDir, true, []string{"notes"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var output strings.Builder
+	capture := captureOutput(&output)
+	err = runResumeInstall()
+	capture.restore()
+	if err != nil {
+		t.Fatalf("Expected resumed bundle install to succeed, got: %v\n%s", err, output.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".local", "share", "devenv-test-notes"))
+	if err != nil || string(data) != "notes" {
+		t.Errorf("Expected the bundled file to be installed, got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/petersenjoern/devenv/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const (
+	rollbackNothingMsg = "Nothing to roll back"
+	rollbackDoneMsg    = "Last run was already rolled back"
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Revert the files written by the last install run",
+	Long: `Revert the filesystem changes devenv itself made during the last
+install run: config files that were replaced are restored from their
+backups, and binaries and configs that were created are removed.
+Packages installed with apt or by install scripts are not removed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lock, err := acquireRunLock()
+		if err != nil {
+			return err
+		}
+		defer lock.Release()
+
+		dir, err := state.DefaultDir()
+		if err != nil {
+			return fmt.Errorf("locating state directory: %w", err)
+		}
+
+		journal, err := state.LoadJournal(dir)
+		if errors.Is(err, state.ErrNoJournal) {
+			fmt.Println(rollbackNothingMsg)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading journal: %w", err)
+		}
+
+		if journal.RolledBack {
+			fmt.Println(rollbackDoneMsg)
+			return nil
+		}
+		if len(journal.Changes) == 0 {
+			fmt.Println(rollbackNothingMsg)
+			return nil
+		}
+
+		reverted, err := journal.Rollback(newCommandExecutor(nil, nil))
+		fmt.Print(FormatRevertedChanges(reverted))
+		if err != nil {
+			return fmt.Errorf("rolling back: %w", err)
+		}
+		return nil
+	},
+}
+
+// FormatRevertedChanges describes each reverted change, one per line.
+func FormatRevertedChanges(changes []state.Change) string {
+	var output strings.Builder
+	for _, change := range changes {
+		switch change.Kind {
+		case state.ChangeReplaced:
+			fmt.Fprintf(&output, "Restored %s (%s)\n", change.Path, change.Tool)
+		default:
+			fmt.Fprintf(&output, "Removed %s (%s)\n", change.Path, change.Tool)
+		}
+	}
+	return output.String()
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+}
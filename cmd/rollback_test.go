@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/state"
+)
+
+func TestRollbackCommand_ShouldFormatRevertedChanges(t *testing.T) {
+	changes := []state.Change{
+		{Tool: "broot", Kind: state.ChangeCreated, Path: "/usr/local/bin/broot"},
+		{Tool: "tmux", Kind: state.ChangeReplaced, Path: "/home/dev/.tmux.conf"},
+	}
+
+	output := FormatRevertedChanges(changes)
+
+	if !strings.Contains(output, "Removed /usr/local/bin/broot (broot)") {
+		t.Errorf("Expected removed binary in output, got: %s", output)
+	}
+	if !strings.Contains(output, "Restored /home/dev/.tmux.conf (tmux)") {
+		t.Errorf("Expected restored config in output, got: %s", output)
+	}
+}
+
+func TestRollbackCommand_ShouldRefuseWhileAnotherRunHoldsTheLock(t *testing.T) {
+	// Test that rollback leaves the journal alone while another run holds the lock
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir, err := state.DefaultDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := state.AcquireLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Release()
+
+	err = rollbackCmd.RunE(rollbackCmd, nil)
+
+	var locked *state.LockedError
+	if !errors.As(err, &locked) {
+		t.Errorf("Expected rollback to fail on the run lock, got: %v", err)
+	}
+}
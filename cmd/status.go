@@ -27,11 +27,8 @@ var statusCmd = &cobra.Command{
 	Long: `Display table showing installation status for all tools.
 Shows binary installation status, configuration status, versions, and paths.
 Use --verbose flag for detailed output.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		err := executeStatusCommand(verbose)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return executeStatusCommand(verbose)
 	},
 }
 
@@ -41,12 +38,14 @@ func executeStatusCommand(verbose bool) error {
 		return fmt.Errorf("finding config: %w", err)
 	}
 
-	cfg, err := config.LoadConfig(configPath)
+	cfg, err := loadConfig(configPath)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
 	detector := detector.New()
+	detector.Logger = logger
+	detector.Scripts = CreateInstallationOrchestrator().ScriptInstaller // Scripts with a check verb
 	statusTable := GenerateStatusTable(cfg, detector, verbose)
 	fmt.Print(statusTable)
 	return nil
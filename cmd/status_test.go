@@ -68,9 +68,12 @@ func TestStatusCommand_ShouldIntegrateWithConfigAndDetector(t *testing.T) {
 	var output strings.Builder
 	originalOutput := captureOutput(&output)
 
-	statusCmd.Run(statusCmd, []string{})
+	err := statusCmd.RunE(statusCmd, []string{})
 
 	originalOutput.restore()
+	if err != nil {
+		t.Fatalf("Status command failed: %v", err)
+	}
 	outputStr := output.String()
 
 	// Should show actual status table headers
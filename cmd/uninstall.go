@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/petersenjoern/devenv/internal/installer"
+	"github.com/spf13/cobra"
+)
+
+const uninstallHeader = "\n=== Uninstall Results ==="
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall <tool>...",
+	Short: "Remove installed tools",
+	Long: `Remove tools by their key in the configuration. Packages installed
+with apt are removed together with the apt repository devenv added for
+them, downloaded binaries and git clones are deleted, and steps pipelines
+are reverted. Install scripts are run with their uninstall verb; legacy
+scripts without one and manual tools cannot be removed automatically.
+Tools installed with --user are removed from ~/.local.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := findConfigPath()
+		if err != nil {
+			return err
+		}
+
+		results, err := UninstallTools(args, configPath)
+		if err != nil {
+			return fmt.Errorf("uninstalling: %w", err)
+		}
+
+		displayUninstallResults(args, results)
+		return resultsError(results, "uninstalled")
+	},
+}
+
+// UninstallTools removes the named tools while holding the run lock.
+func UninstallTools(toolNames []string, configPath string) (map[string]installer.InstallationResult, error) {
+	toolConfigs, err := LoadToolConfigurations(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tool configurations: %w", err)
+	}
+
+	for _, toolName := range toolNames {
+		if _, exists := toolConfigs[toolName]; !exists {
+			return nil, fmt.Errorf("unknown tool: %s", toolName)
+		}
+	}
+
+	lock, err := acquireRunLock()
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	return byInstallMode(toolNames, toolConfigs, func(orchestrator *installer.InstallationOrchestrator, tools []string) map[string]installer.InstallationResult {
+		return orchestrator.Uninstall(tools, toolConfigs)
+	})
+}
+
+func displayUninstallResults(toolNames []string, results map[string]installer.InstallationResult) {
+	fmt.Println(uninstallHeader)
+	for _, toolName := range toolNames {
+		result := results[toolName]
+		if result.Status != installer.StatusFailed {
+			fmt.Printf("%s %s (%s) - removed\n", successIcon, result.Tool.DisplayName, toolName)
+		} else {
+			fmt.Printf("%s %s (%s) - %v\n", failureIcon, result.Tool.DisplayName, toolName, result.Error)
+		}
+	}
+}
+
+func init() {
+	uninstallCmd.Flags().BoolVar(&userMode, "user", false,
+		"Remove tools from ~/.local; tools installed with --user are found there without it")
+	rootCmd.AddCommand(uninstallCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/installer"
+	"github.com/petersenjoern/devenv/internal/tui"
+)
+
+func TestUninstallTools_ShouldRemoveUserInstallsFromUserPrefix(t *testing.T) {
+	// Test that a tool installed with --user is uninstalled from ~/.local without passing --user again
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Cleanup(func() { userMode = false })
+
+	configPath := filepath.Join(dir, "config.yaml")
+	catalog := `categories:
+  utilities:
+    notes:
+      display_name: "Notes"
+      binary_name: "devenv-test-notes"
+      install_method: "steps"
+      steps:
+        - action: "run"
+          command: "mkdir -p {prefix}/share && touch {prefix}/share/devenv-test-notes"
+          undo: "rm {prefix}/share/devenv-test-notes"
+`
+	if err := os.WriteFile(configPath, []byte(catalog), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	installed := filepath.Join(home, ".local", "share", "devenv-test-notes")
+
+	var output strings.Builder
+	capture := captureOutput(&output)
+	defer capture.restore()
+
+	userMode = true
+	selections := tui.Selections{CategoryAndTools: []tui.CategoryAndTools{{Category: "utilities", Tools: []string{"notes"}}}}
+	results, err := executeInstallationsWith(CreateInstallationOrchestrator(), selections, configPath)
+	if err != nil || results["notes"].Status != installer.StatusInstalled {
+		t.Fatalf("Expected user install to succeed, got %v (%v)", results["notes"].Error, err)
+	}
+	if _, err := os.Stat(installed); err != nil {
+		t.Fatalf("Expected install into the user prefix, got: %v", err)
+	}
+
+	userMode = false
+	results, err = UninstallTools([]string{"notes"}, configPath)
+	if err != nil || results["notes"].Status != installer.StatusRemoved {
+		t.Fatalf("Expected uninstall to succeed, got %v (%v)", results["notes"].Error, err)
+	}
+	if _, err := os.Stat(installed); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed, got: %v", installed, err)
+	}
+	if userMode {
+		t.Errorf("Expected --user to be restored after uninstall")
+	}
+}
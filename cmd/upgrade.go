@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/petersenjoern/devenv/internal/installer"
+	"github.com/spf13/cobra"
+)
+
+const upgradeHeader = "\n=== Upgrade Results ==="
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade <tool>...",
+	Short: "Upgrade installed tools",
+	Long: `Upgrade tools by their key in the configuration. Packages installed
+with apt are upgraded to the newest available version and install scripts
+are run with their upgrade verb; legacy scripts without one are run again.
+Downloads, git clones and steps pipelines are installed again, which picks
+up the version in the configuration. Manual tools cannot be upgraded
+automatically. Tools installed with --user are upgraded in ~/.local.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := findConfigPath()
+		if err != nil {
+			return err
+		}
+
+		results, err := UpgradeTools(args, configPath)
+		if err != nil {
+			return fmt.Errorf("upgrading: %w", err)
+		}
+
+		displayUpgradeResults(args, results)
+		return resultsError(results, "upgraded")
+	},
+}
+
+// UpgradeTools upgrades the named tools while holding the run lock.
+func UpgradeTools(toolNames []string, configPath string) (map[string]installer.InstallationResult, error) {
+	toolConfigs, err := LoadToolConfigurations(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tool configurations: %w", err)
+	}
+
+	for _, toolName := range toolNames {
+		if _, exists := toolConfigs[toolName]; !exists {
+			return nil, fmt.Errorf("unknown tool: %s", toolName)
+		}
+	}
+
+	lock, err := acquireRunLock()
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	return byInstallMode(toolNames, toolConfigs, func(orchestrator *installer.InstallationOrchestrator, tools []string) map[string]installer.InstallationResult {
+		return orchestrator.Upgrade(tools, toolConfigs)
+	})
+}
+
+func displayUpgradeResults(toolNames []string, results map[string]installer.InstallationResult) {
+	fmt.Println(upgradeHeader)
+	for _, toolName := range toolNames {
+		result := results[toolName]
+		if result.Status != installer.StatusFailed {
+			fmt.Printf("%s %s (%s) - upgraded\n", successIcon, result.Tool.DisplayName, toolName)
+		} else {
+			fmt.Printf("%s %s (%s) - %v\n", failureIcon, result.Tool.DisplayName, toolName, result.Error)
+		}
+	}
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&userMode, "user", false,
+		"Upgrade tools in ~/.local; tools installed with --user are upgraded there without it")
+	rootCmd.AddCommand(upgradeCmd)
+}
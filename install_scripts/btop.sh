@@ -5,22 +5,20 @@
 
 set -e
 
-echo "Installing btop..."
+# Helper library: DEVENV_LIB when run by devenv, else the copy next to this script
+. "${DEVENV_LIB:-$(dirname "$0")/lib/devenv.sh}"
 
-# Install btop
-sudo apt update
-sudo apt install -y btop
+devenv_info "Installing btop..."
+
+# Install btop (needs apt, so unavailable for rootless installs)
+if [ "$(devenv_prefix)" = "/usr/local" ]; then
+    sudo apt update
+    sudo apt install -y btop
+elif ! command -v btop >/dev/null; then
+    devenv_die "btop is installed with apt, which is unavailable in --user mode"
+fi
 
 # Create config directory
 mkdir -p ~/.config/btop/themes
 
-# Verify installation
-if command -v btop &> /dev/null; then
-    echo "btop installed successfully"
-    btop --version
-else
-    echo "btop installation failed"
-    exit 1
-fi
-
-echo "btop installation complete"
\ No newline at end of file
+devenv_verify_binary btop
@@ -0,0 +1,96 @@
+#!/bin/bash
+
+# DevEnv - Install Script Helper Library
+# Sourced automatically into every install script devenv runs (through
+# BASH_ENV) and available to source explicitly from "$DEVENV_LIB".
+#
+# Log records are written to DEVENV_LOG_FILE, when set, as one
+# "<level><TAB><message>" line each so devenv can report them.
+
+# Guard against being sourced twice, e.g. by BASH_ENV and explicitly
+[ -n "$DEVENV_LIB_LOADED" ] && return 0
+DEVENV_LIB_LOADED=1
+
+# devenv_log LEVEL MESSAGE... - log a message at debug, info, warn or error
+devenv_log() {
+    local level="$1"
+    shift
+    echo "[devenv] $level: $*" >&2
+    if [ -n "$DEVENV_LOG_FILE" ]; then
+        printf '%s\t%s\n' "$level" "$*" >> "$DEVENV_LOG_FILE"
+    fi
+}
+
+devenv_info() { devenv_log info "$@"; }
+devenv_warn() { devenv_log warn "$@"; }
+devenv_error() { devenv_log error "$@"; }
+
+# devenv_die MESSAGE... - log an error and exit the script
+devenv_die() {
+    devenv_error "$@"
+    exit 1
+}
+
+# devenv_arch [AMD64_NAME] [ARM64_NAME] - print the architecture name a
+# release uses (x86_64 and aarch64 by default); exits for other architectures
+devenv_arch() {
+    case "${DEVENV_ARCH:-$(uname -m)}" in
+        amd64|x86_64) echo "${1:-x86_64}" ;;
+        arm64|aarch64) echo "${2:-aarch64}" ;;
+        *) devenv_die "unsupported architecture: ${DEVENV_ARCH:-$(uname -m)}" ;;
+    esac
+}
+
+# devenv_prefix - print the install prefix (~/.local in --user mode)
+devenv_prefix() {
+    echo "${DEVENV_PREFIX:-/usr/local}"
+}
+
+# devenv_sudo DIR - print "sudo" when DIR is not writable by the current user
+devenv_sudo() {
+    mkdir -p "$1" 2>/dev/null || true
+    if [ -w "$1" ]; then echo ""; else echo "sudo"; fi
+}
+
+# devenv_download URL DEST [SHA256] - download URL to DEST and verify its
+# checksum; uses the copy from an offline bundle when there is one
+devenv_download() {
+    local url="$1" dest="$2" sha256="$3"
+    local bundled="$DEVENV_BUNDLE_DIR/artifacts/$(basename "$dest")"
+
+    if [ -n "$DEVENV_BUNDLE_DIR" ] && [ -f "$bundled" ]; then
+        cp "$bundled" "$dest" || devenv_die "failed to copy $bundled"
+    else
+        curl -fsSL -o "$dest" "$url" || devenv_die "failed to download $url"
+    fi
+
+    if [ -n "$sha256" ] && ! echo "$sha256  $dest" | sha256sum -c --status -; then
+        rm -f "$dest"
+        devenv_die "checksum mismatch for $(basename "$dest")"
+    fi
+}
+
+# devenv_github_latest OWNER/REPO - print the latest release version of a
+# GitHub repository without a leading "v"
+devenv_github_latest() {
+    local tag
+    tag=$(curl -fsSL "https://api.github.com/repos/$1/releases/latest" | sed -n 's/.*"tag_name": *"\([^"]*\)".*/\1/p' | head -1)
+    [ -n "$tag" ] || devenv_die "failed to look up the latest release of $1"
+    echo "${tag#v}"
+}
+
+# devenv_verify_binary NAME [VERSION_ARGS...] - check NAME is installed and
+# log its version (from --version unless other arguments are given)
+devenv_verify_binary() {
+    local name="$1"
+    shift
+    local binary
+    binary=$(command -v "$name" || true)
+    if [ -z "$binary" ] && [ -x "$(devenv_prefix)/bin/$name" ]; then
+        binary="$(devenv_prefix)/bin/$name"
+    fi
+    [ -n "$binary" ] || devenv_die "$name installation failed: $name not found"
+
+    [ $# -gt 0 ] || set -- --version
+    devenv_info "$name installed successfully: $("$binary" "$@" 2>&1 | head -1)"
+}
@@ -2,24 +2,40 @@
 
 # DevEnv - Mise Installation Script
 # Installs mise (formerly rtx) runtime manager
+# devenv-verbs: check install upgrade uninstall version
 
 set -e
 
-echo "Installing mise..."
+# Helper library: DEVENV_LIB when run by devenv, else the copy next to this script
+. "${DEVENV_LIB:-$(dirname "$0")/lib/devenv.sh}"
 
-# Download and install mise
-curl https://mise.run | sh
+MISE_BIN="$HOME/.local/bin/mise"
 
-# Add to PATH in current session
-export PATH="$HOME/.local/bin:$PATH"
+case "${1:-install}" in
+    check)
+        [ -x "$MISE_BIN" ] || command -v mise &> /dev/null || exit 1
+        ;;
+    version)
+        "$MISE_BIN" --version
+        ;;
+    install)
+        devenv_info "Installing mise..."
 
-# Verify installation
-if command -v mise &> /dev/null; then
-    echo "mise installed successfully"
-    mise --version
-else
-    echo "mise installation failed"
-    exit 1
-fi
+        # Download and install mise
+        curl https://mise.run | sh
 
-echo "mise installation complete"
\ No newline at end of file
+        DEVENV_PREFIX="$HOME/.local" devenv_verify_binary mise
+        ;;
+    upgrade)
+        devenv_info "Upgrading mise..."
+        "$MISE_BIN" self-update --yes
+        ;;
+    uninstall)
+        devenv_info "Uninstalling mise..."
+        "$MISE_BIN" implode --yes
+        ;;
+    *)
+        devenv_error "Unknown verb: $1"
+        exit 2
+        ;;
+esac
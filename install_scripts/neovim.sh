@@ -5,41 +5,50 @@
 
 set -e
 
-echo "Installing Neovim..."
+# Helper library: DEVENV_LIB when run by devenv, else the copy next to this script
+. "${DEVENV_LIB:-$(dirname "$0")/lib/devenv.sh}"
+
+devenv_info "Installing Neovim..."
+
+# Map the architecture to the release asset naming
+NVIM_DIR="nvim-linux-$(devenv_arch x86_64 arm64)"
+
+# Install into DEVENV_PREFIX (~/.local in --user mode), using sudo only when needed
+PREFIX="$(devenv_prefix)"
+SUDO="$(devenv_sudo "$PREFIX/bin")"
 
 # Install Neovim from official releases
 cd /tmp
-wget -O nvim.tar.gz "https://github.com/neovim/neovim/releases/download/stable/nvim-linux-x86_64.tar.gz"
+NVIM_RELEASE="https://github.com/neovim/neovim/releases/download/stable"
+devenv_download "$NVIM_RELEASE/shasum.txt" nvim-shasum.txt
+NVIM_SHA256=$(awk -v asset="${NVIM_DIR}.tar.gz" '$2 == asset { print $1 }' nvim-shasum.txt)
+[ -n "$NVIM_SHA256" ] || devenv_die "no checksum published for ${NVIM_DIR}.tar.gz"
+devenv_download "$NVIM_RELEASE/${NVIM_DIR}.tar.gz" nvim.tar.gz "$NVIM_SHA256"
 tar -xf nvim.tar.gz
-sudo install nvim-linux-x86_64/bin/nvim /usr/local/bin/nvim
-sudo cp -R nvim-linux-x86_64/lib /usr/local/
-sudo cp -R nvim-linux-x86_64/share /usr/local/
-rm -rf nvim-linux-x86_64 nvim.tar.gz
+$SUDO install "${NVIM_DIR}/bin/nvim" "$PREFIX/bin/nvim"
+$SUDO cp -R "${NVIM_DIR}/lib" "$PREFIX/"
+$SUDO cp -R "${NVIM_DIR}/share" "$PREFIX/"
+rm -rf "${NVIM_DIR}" nvim.tar.gz nvim-shasum.txt
 cd -
 
-# Install supporting tools
-sudo apt install -y luarocks
+# Install supporting tools (needs apt, so skipped for rootless installs)
+if [ "$PREFIX" = "/usr/local" ]; then
+    sudo apt install -y luarocks
+else
+    devenv_warn "Skipping luarocks: install it with your package manager"
+fi
 
 # Create nvim config directory if it doesn't exist
 mkdir -p ~/.config/nvim
 
 # Only set up LazyVim if config doesn't exist
 if [ ! -f ~/.config/nvim/init.lua ]; then
-    echo "Setting up LazyVim configuration..."
+    devenv_info "Setting up LazyVim configuration..."
     # Clone LazyVim starter
     git clone https://github.com/LazyVim/starter ~/.config/nvim
     # Remove .git to allow user to add to their own repo
     rm -rf ~/.config/nvim/.git
-    echo "LazyVim configuration installed"
-fi
-
-# Verify installation
-if command -v nvim &> /dev/null; then
-    echo "Neovim installed successfully"
-    nvim --version | head -1
-else
-    echo "Neovim installation failed"
-    exit 1
+    devenv_info "LazyVim configuration installed"
 fi
 
-echo "Neovim installation complete"
\ No newline at end of file
+devenv_verify_binary nvim
\ No newline at end of file
@@ -4,7 +4,10 @@
 
 set -e
 
-echo "Installing NVM..."
+# Helper library: DEVENV_LIB when run by devenv, else the copy next to this script
+. "${DEVENV_LIB:-$(dirname "$0")/lib/devenv.sh}"
+
+devenv_info "Installing NVM..."
 
 
 # Download and install NVM
@@ -16,13 +19,6 @@ export NVM_DIR="$HOME/.nvm"
 [ -s "$NVM_DIR/bash_completion" ] && \. "$NVM_DIR/bash_completion"
 
 
-# Verify installation
-if command -v nvm &> /dev/null && command -v node &> /dev/null; then
-    echo "NVM and Node.js installed successfully"
-    nvm --version
-else
-    echo "NVM installation failed"
-    exit 1
-fi
-
-echo "NVM installation completed"
\ No newline at end of file
+# Verify installation (nvm is a shell function, not a binary)
+command -v nvm &> /dev/null || devenv_die "NVM installation failed: nvm not found"
+devenv_info "NVM installed successfully: $(nvm --version)"
\ No newline at end of file
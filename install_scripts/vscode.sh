@@ -5,7 +5,15 @@
 
 set -e
 
-echo "Installing Visual Studio Code..."
+# Helper library: DEVENV_LIB when run by devenv, else the copy next to this script
+. "${DEVENV_LIB:-$(dirname "$0")/lib/devenv.sh}"
+
+devenv_info "Installing Visual Studio Code..."
+
+# The Microsoft apt repository needs root, so rootless installs cannot use it
+if [ "$(devenv_prefix)" != "/usr/local" ]; then
+    devenv_die "VS Code is installed with apt, which is unavailable in --user mode"
+fi
 
 # Add Microsoft GPG key and repository
 cd /tmp
@@ -22,13 +30,4 @@ sudo apt install -y code
 # Create config directory
 mkdir -p ~/.config/Code/User
 
-# Verify installation
-if command -v code &> /dev/null; then
-    echo "VS Code installed successfully"
-    code --version | head -1
-else
-    echo "VS Code installation failed"
-    exit 1
-fi
-
-echo "VS Code installation complete"
\ No newline at end of file
+devenv_verify_binary code
\ No newline at end of file
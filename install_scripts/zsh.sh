@@ -5,11 +5,18 @@
 
 set -e
 
-echo "Installing Zsh..."
+# Helper library: DEVENV_LIB when run by devenv, else the copy next to this script
+. "${DEVENV_LIB:-$(dirname "$0")/lib/devenv.sh}"
 
-# Install Zsh
-sudo apt update
-sudo apt install -y zsh
+devenv_info "Installing Zsh..."
+
+# Install Zsh (needs apt; rootless installs only set up Oh My Zsh)
+if [ "$(devenv_prefix)" = "/usr/local" ]; then
+    sudo apt update
+    sudo apt install -y zsh
+elif ! command -v zsh >/dev/null; then
+    devenv_die "zsh is installed with apt, which is unavailable in --user mode"
+fi
 
 # Install Oh My Zsh (non-interactive)
 RUNZSH=no CHSH=no sh -c "$(curl -fsSL https://raw.githubusercontent.com/ohmyzsh/ohmyzsh/master/tools/install.sh)"
@@ -37,14 +44,5 @@ if [ ! -d "$ZSH_CUSTOM/themes/powerlevel10k" ]; then
     git clone --depth=1 https://github.com/romkatv/powerlevel10k.git "$ZSH_CUSTOM/themes/powerlevel10k"
 fi
 
-# Verify installation
-if command -v zsh &> /dev/null; then
-    echo "Zsh installed successfully"
-    zsh --version
-else
-    echo "Zsh installation failed"
-    exit 1
-fi
-
-echo "Zsh installation complete with Oh My Zsh and plugins"
-echo "Note: Use 'chsh -s $(which zsh)' to set zsh as default shell"
\ No newline at end of file
+devenv_verify_binary zsh
+devenv_info "Use 'chsh -s $(which zsh)' to set zsh as default shell"
\ No newline at end of file
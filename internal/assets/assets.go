@@ -0,0 +1,112 @@
+package assets
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+)
+
+const tempDirPattern = "devenv-assets-"
+
+// Source resolves the default catalog, install scripts and templates.
+// Files present on disk take precedence; otherwise the copy embedded in
+// the binary is extracted to a temporary directory on first use.
+type Source struct {
+	embedded fs.FS
+
+	mu         sync.Mutex
+	extractDir string
+}
+
+func New(embedded fs.FS) *Source {
+	return &Source{embedded: embedded}
+}
+
+// Resolve returns a path on disk for name, extracting the embedded copy
+// when name does not exist relative to the working directory.
+func (s *Source) Resolve(name string) (string, error) {
+	if _, err := os.Stat(name); err == nil {
+		return name, nil
+	}
+
+	if s == nil || s.embedded == nil {
+		return "", fmt.Errorf("%s not found on disk and no embedded assets available", name)
+	}
+
+	return s.extract(name)
+}
+
+// HasEmbedded reports whether name is part of the embedded assets.
+func (s *Source) HasEmbedded(name string) bool {
+	if s == nil || s.embedded == nil {
+		return false
+	}
+	_, err := fs.Stat(s.embedded, embeddedName(name))
+	return err == nil
+}
+
+// Cleanup removes the temporary extraction directory, if one was created.
+func (s *Source) Cleanup() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.extractDir == "" {
+		return nil
+	}
+
+	err := os.RemoveAll(s.extractDir)
+	s.extractDir = ""
+	return err
+}
+
+func (s *Source) extract(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	embeddedPath := embeddedName(name)
+	content, err := fs.ReadFile(s.embedded, embeddedPath)
+	if err != nil {
+		return "", fmt.Errorf("%s not found on disk or in embedded assets: %w", name, err)
+	}
+
+	if s.extractDir == "" {
+		dir, err := os.MkdirTemp("", tempDirPattern)
+		if err != nil {
+			return "", fmt.Errorf("failed to create asset directory: %w", err)
+		}
+		s.extractDir = dir
+	}
+
+	target := filepath.Join(s.extractDir, filepath.FromSlash(embeddedPath))
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory for %s: %w", name, err)
+	}
+
+	if err := os.WriteFile(target, content, fileMode(embeddedPath)); err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+
+	return target, nil
+}
+
+func embeddedName(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
+
+func fileMode(name string) os.FileMode {
+	if path.Ext(name) == ".sh" {
+		return 0o755
+	}
+	return 0o644
+}
@@ -0,0 +1,87 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestResolve_ShouldPreferFileOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	onDisk := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(onDisk, []byte("categories: {}"), 0o644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	source := New(fstest.MapFS{
+		"config.yaml": {Data: []byte("embedded")},
+	})
+	defer source.Cleanup()
+
+	resolved, err := source.Resolve(onDisk)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resolved != onDisk {
+		t.Errorf("Expected on-disk path %s, got %s", onDisk, resolved)
+	}
+}
+
+func TestResolve_ShouldExtractEmbeddedFileWhenMissingOnDisk(t *testing.T) {
+	source := New(fstest.MapFS{
+		"install_scripts/missing-tool.sh": {Data: []byte("#!/bin/bash\necho ok\n")},
+	})
+	defer source.Cleanup()
+
+	resolved, err := source.Resolve("install_scripts/missing-tool.sh")
+	if err != nil {
+		t.Fatalf("Expected embedded script to be extracted, got error: %v", err)
+	}
+
+	content, err := os.ReadFile(resolved)
+	if err != nil {
+		t.Fatalf("Expected extracted file to be readable, got: %v", err)
+	}
+
+	if string(content) != "#!/bin/bash\necho ok\n" {
+		t.Errorf("Expected extracted content to match embedded file, got: %q", content)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		t.Fatalf("Failed to stat extracted file: %v", err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("Expected extracted script to be executable, got mode %v", info.Mode())
+	}
+}
+
+func TestResolve_ShouldFailWhenFileIsNowhere(t *testing.T) {
+	source := New(fstest.MapFS{})
+
+	_, err := source.Resolve("install_scripts/does-not-exist.sh")
+	if err == nil {
+		t.Errorf("Expected error for file missing on disk and in embedded assets")
+	}
+}
+
+func TestCleanup_ShouldRemoveExtractedFiles(t *testing.T) {
+	source := New(fstest.MapFS{
+		"templates/missing.conf": {Data: []byte("set -g mouse on")},
+	})
+
+	resolved, err := source.Resolve("templates/missing.conf")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := source.Cleanup(); err != nil {
+		t.Fatalf("Expected cleanup to succeed, got: %v", err)
+	}
+
+	if _, err := os.Stat(resolved); !os.IsNotExist(err) {
+		t.Errorf("Expected extracted file to be removed after cleanup")
+	}
+}
@@ -0,0 +1,427 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/download"
+)
+
+const (
+	ManifestFile  = "manifest.json"
+	ChecksumsFile = "SHA256SUMS"
+
+	manifestVersion = 1
+
+	downloadsDir = "downloads"
+	debsDir      = "debs"
+	artifactsDir = "artifacts"
+	reposDir     = "repos"
+
+	// aptDownloadCmd downloads a package with its dependency closure, so it
+	// installs on a target machine without mirrors. Virtual packages, listed
+	// in angle brackets, are left out.
+	aptDownloadCmd = "cd %s && apt-get download $(apt-cache depends --recurse --no-recommends --no-suggests " +
+		"--no-conflicts --no-breaks --no-replaces --no-enhances %s | grep '^[[:alnum:]]' | sort -u)"
+
+	// gitBundleCmd mirrors a repository and packs every ref into a single
+	// git bundle file that can be cloned from.
+	gitBundleCmd = "git clone --quiet --mirror %s %s && git -C %s bundle create %s --all"
+)
+
+// Artifact kinds stored in a bundle.
+const (
+	KindDownload = "download"
+	KindDeb      = "deb"
+	KindArtifact = "artifact"
+	KindRepo     = "repo"
+)
+
+// CommandExecutor runs shell commands, e.g. apt-get download or git clone.
+type CommandExecutor interface {
+	Execute(command string) error
+}
+
+// Manifest describes the content of an offline bundle.
+type Manifest struct {
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	Arch      string     `json:"arch"`
+	Tools     []string   `json:"tools"`
+	Artifacts []Artifact `json:"artifacts"`
+	Warnings  []string   `json:"warnings,omitempty"`
+}
+
+// Artifact is a single file in the bundle.
+type Artifact struct {
+	Tool   string `json:"tool"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+}
+
+// Creator downloads everything a set of tools needs into a bundle directory.
+type Creator struct {
+	Downloader      download.Downloader
+	CommandExecutor CommandExecutor
+	// Arch is the architecture of the machine the bundle is meant for.
+	Arch string
+}
+
+// Create fetches the artifacts for tools (in installation order) into dir and
+// writes the manifest and checksum files.
+func (c *Creator) Create(dir string, toolNames []string, tools map[string]config.ToolConfig) (Manifest, error) {
+	manifest := Manifest{
+		Version:   manifestVersion,
+		CreatedAt: time.Now().UTC(),
+		Arch:      c.Arch,
+		Tools:     toolNames,
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return manifest, fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+
+	for _, toolName := range toolNames {
+		tool, exists := tools[toolName]
+		if !exists {
+			return manifest, fmt.Errorf("unknown tool: %s", toolName)
+		}
+
+		if !config.SupportsArchitecture(tool, c.Arch) {
+			manifest.Warnings = append(manifest.Warnings, fmt.Sprintf("%s: %s %s", toolName, config.ErrUnsupportedArchitecture, c.Arch))
+			continue
+		}
+
+		artifacts, warnings, err := c.collect(dir, toolName, tool)
+		if err != nil {
+			return manifest, fmt.Errorf("failed to bundle %s: %w", toolName, err)
+		}
+		manifest.Artifacts = append(manifest.Artifacts, artifacts...)
+		manifest.Warnings = append(manifest.Warnings, warnings...)
+	}
+
+	if err := writeManifest(dir, manifest); err != nil {
+		return manifest, err
+	}
+
+	return manifest, writeChecksums(dir, manifest)
+}
+
+func (c *Creator) collect(dir, toolName string, tool config.ToolConfig) ([]Artifact, []string, error) {
+	var artifacts []Artifact
+	var warnings []string
+
+	// Fallback chains bundle what every method needs, since the target
+	// machine decides which one is used.
+	for _, method := range config.Methods(tool) {
+		switch method {
+		case "apt":
+			for _, packageName := range strings.Fields(tool.PackageName) {
+				debs, err := c.fetchDebs(dir, toolName, packageName)
+				if err != nil {
+					return nil, nil, err
+				}
+				artifacts = append(artifacts, debs...)
+			}
+		case "download":
+			url, err := config.ResolveDownloadURL(tool, c.Arch)
+			if errors.Is(err, config.ErrUnsupportedArchitecture) && len(tool.InstallMethods) > 0 {
+				continue // Another method of the chain covers this architecture
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+
+			file := path.Join(downloadsDir, toolName, path.Base(url))
+			artifact, err := c.fetch(dir, file, url, config.ResolveChecksum(tool, c.Arch))
+			if err != nil {
+				return nil, nil, err
+			}
+			artifact.Tool, artifact.Kind, artifact.Name = toolName, KindDownload, tool.BinaryName
+			artifacts = append(artifacts, artifact)
+		case "steps":
+			for _, step := range tool.Steps {
+				switch step.Action {
+				case config.StepPackage:
+					for _, packageName := range strings.Fields(step.Package) {
+						debs, err := c.fetchDebs(dir, toolName, packageName)
+						if err != nil {
+							return nil, nil, err
+						}
+						artifacts = append(artifacts, debs...)
+					}
+				case config.StepDownload:
+					url, err := config.ExpandStepValue(tool, step.URL, c.Arch)
+					if err != nil {
+						return nil, nil, err
+					}
+
+					file := path.Join(downloadsDir, toolName, path.Base(url))
+					artifact, err := c.fetch(dir, file, url, step.SHA256)
+					if err != nil {
+						return nil, nil, err
+					}
+					artifact.Tool, artifact.Kind, artifact.Name = toolName, KindDownload, path.Base(url)
+					artifacts = append(artifacts, artifact)
+				}
+			}
+		case "git":
+			if tool.Git == nil || tool.Git.Repo == "" {
+				return nil, nil, fmt.Errorf("git repo is required for git installation method")
+			}
+			artifact, err := c.fetchRepo(dir, toolName, tool.Git.Repo)
+			if err != nil {
+				return nil, nil, err
+			}
+			artifacts = append(artifacts, artifact)
+		case "script":
+			if len(tool.Artifacts) == 0 {
+				warnings = append(warnings, fmt.Sprintf("%s: install script declares no artifacts and may need network access", toolName))
+			}
+		}
+	}
+
+	for _, declared := range tool.Artifacts {
+		url, err := config.ExpandArch(tool, declared.URL, c.Arch)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		file := path.Join(artifactsDir, declared.Name)
+		artifact, err := c.fetch(dir, file, url, declared.SHA256)
+		if err != nil {
+			return nil, nil, err
+		}
+		artifact.Tool, artifact.Kind, artifact.Name = toolName, KindArtifact, declared.Name
+		artifacts = append(artifacts, artifact)
+	}
+
+	return artifacts, warnings, nil
+}
+
+func (c *Creator) fetch(dir, file, url, expectedSHA256 string) (Artifact, error) {
+	target := filepath.Join(dir, filepath.FromSlash(file))
+	if err := c.Downloader.Download(url, expectedSHA256, target); err != nil {
+		return Artifact{}, err
+	}
+
+	checksum, err := download.FileSHA256(target)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	return Artifact{Source: url, File: file, SHA256: checksum}, nil
+}
+
+// fetchRepo packs repo into repos/<tool>.bundle, so it can be cloned on the
+// target machine without network access.
+func (c *Creator) fetchRepo(dir, toolName, repo string) (Artifact, error) {
+	file := path.Join(reposDir, toolName+".bundle")
+	target, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(file)))
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to resolve bundle directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return Artifact{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+
+	mirror, err := os.MkdirTemp("", "devenv-mirror-")
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+	defer os.RemoveAll(mirror)
+	mirror = filepath.Join(mirror, "repo.git")
+
+	command := fmt.Sprintf(gitBundleCmd, shellQuote(repo), shellQuote(mirror), shellQuote(mirror), shellQuote(target))
+	if err := c.CommandExecutor.Execute(command); err != nil {
+		return Artifact{}, fmt.Errorf("failed to bundle repository %s: %w", repo, err)
+	}
+
+	checksum, err := download.FileSHA256(target)
+	if err != nil {
+		return Artifact{}, err
+	}
+	return Artifact{Tool: toolName, Kind: KindRepo, Name: toolName, Source: repo, File: file, SHA256: checksum}, nil
+}
+
+// fetchDebs downloads packageName and its dependencies into debs/<package>.
+// The package's own .deb comes first; every artifact has the package as its
+// source so DebPaths can install them together.
+func (c *Creator) fetchDebs(dir, toolName, packageName string) ([]Artifact, error) {
+	targetDir := filepath.Join(dir, debsDir, packageName)
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", targetDir, err)
+	}
+
+	if err := c.CommandExecutor.Execute(fmt.Sprintf(aptDownloadCmd, shellQuote(targetDir), shellQuote(packageName))); err != nil {
+		return nil, fmt.Errorf("failed to download package %s: %w", packageName, err)
+	}
+
+	debFiles, _ := filepath.Glob(filepath.Join(targetDir, "*.deb"))
+	sort.Strings(debFiles)
+
+	var artifacts []Artifact
+	for _, debFile := range debFiles {
+		checksum, err := download.FileSHA256(debFile)
+		if err != nil {
+			return nil, err
+		}
+
+		artifact := Artifact{
+			Tool:   toolName,
+			Kind:   KindDeb,
+			Name:   debPackageName(debFile),
+			Source: packageName,
+			File:   path.Join(debsDir, packageName, filepath.Base(debFile)),
+			SHA256: checksum,
+		}
+		if artifact.Name == packageName {
+			artifacts = append([]Artifact{artifact}, artifacts...)
+		} else {
+			artifacts = append(artifacts, artifact)
+		}
+	}
+
+	if len(artifacts) == 0 || artifacts[0].Name != packageName {
+		return nil, fmt.Errorf("apt-get download produced no .deb for %s", packageName)
+	}
+	return artifacts, nil
+}
+
+// debPackageName returns the package name of a name_version_arch.deb file.
+func debPackageName(debFile string) string {
+	name, _, _ := strings.Cut(filepath.Base(debFile), "_")
+	return name
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeManifest(dir string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// writeChecksums writes a sha256sum compatible file so the bundle can also be
+// verified with standard tools after being copied to the target machine.
+func writeChecksums(dir string, manifest Manifest) error {
+	var sums strings.Builder
+	for _, artifact := range manifest.Artifacts {
+		fmt.Fprintf(&sums, "%s  %s\n", artifact.SHA256, artifact.File)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ChecksumsFile), []byte(sums.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write checksums: %w", err)
+	}
+	return nil
+}
+
+// Bundle is an opened and verified offline bundle.
+type Bundle struct {
+	Dir      string
+	Manifest Manifest
+}
+
+// Open reads the manifest in dir and verifies every artifact checksum.
+func Open(dir string) (*Bundle, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve bundle directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+	}
+
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", manifest.Version)
+	}
+
+	b := &Bundle{Dir: dir, Manifest: manifest}
+	for _, artifact := range manifest.Artifacts {
+		if err := download.VerifySHA256(b.path(artifact), artifact.SHA256); err != nil {
+			return nil, fmt.Errorf("bundle is corrupt: %w", err)
+		}
+	}
+
+	return b, nil
+}
+
+// Download satisfies download.Downloader by copying the bundled file that was
+// fetched from url.
+func (b *Bundle) Download(url, expectedSHA256, dest string) error {
+	for _, artifact := range b.Manifest.Artifacts {
+		if artifact.Kind != KindDeb && artifact.Kind != KindRepo && artifact.Source == url {
+			if err := download.CopyFile(b.path(artifact), dest); err != nil {
+				return err
+			}
+			return download.VerifySHA256(dest, expectedSHA256)
+		}
+	}
+	return fmt.Errorf("%s is not part of the offline bundle", url)
+}
+
+// DebPaths returns the bundled .deb file for packageName followed by those
+// of its dependencies.
+func (b *Bundle) DebPaths(packageName string) ([]string, bool) {
+	var paths []string
+	found := false
+	for _, artifact := range b.Manifest.Artifacts {
+		if artifact.Kind != KindDeb || artifact.Source != packageName {
+			continue
+		}
+		if artifact.Name == packageName {
+			found = true
+		}
+		if !slices.Contains(paths, b.path(artifact)) {
+			paths = append(paths, b.path(artifact))
+		}
+	}
+	return paths, found
+}
+
+// RepoPath returns the bundled git bundle file of repo, which can be cloned
+// and fetched from like the repository itself.
+func (b *Bundle) RepoPath(repo string) (string, bool) {
+	for _, artifact := range b.Manifest.Artifacts {
+		if artifact.Kind == KindRepo && artifact.Source == repo {
+			return b.path(artifact), true
+		}
+	}
+	return "", false
+}
+
+// ArtifactsDir is where declared script artifacts are stored, by name.
+func (b *Bundle) ArtifactsDir() string {
+	return filepath.Join(b.Dir, artifactsDir)
+}
+
+func (b *Bundle) path(artifact Artifact) string {
+	return filepath.Join(b.Dir, filepath.FromSlash(artifact.File))
+}
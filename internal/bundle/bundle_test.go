@@ -0,0 +1,283 @@
+package bundle
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/download"
+)
+
+// fakeAPTExecutor simulates downloading a package and its libfake
+// dependency by writing .debs into the directory the command changes into.
+type fakeAPTExecutor struct {
+	ExecutedCommands []string
+}
+
+func (f *fakeAPTExecutor) Execute(command string) error {
+	f.ExecutedCommands = append(f.ExecutedCommands, command)
+
+	dir := strings.Trim(strings.Fields(command)[1], "'")
+	_, rest, _ := strings.Cut(command, "--no-enhances ")
+	packageName := strings.Trim(strings.Fields(rest)[0], "'")
+
+	for _, name := range []string{packageName, "libfake"} {
+		if err := os.WriteFile(filepath.Join(dir, name+"_1.0_amd64.deb"), []byte("deb:"+name), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newArtifactServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/broot":
+			fmt.Fprint(w, "broot-binary")
+		case "/lazygit.tar.gz":
+			fmt.Fprint(w, "lazygit-archive")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testTools(serverURL string) map[string]config.ToolConfig {
+	return map[string]config.ToolConfig{
+		"git": {
+			DisplayName:   "Git Version Control",
+			BinaryName:    "git",
+			InstallMethod: "apt",
+			PackageName:   "git",
+		},
+		"broot": {
+			DisplayName:   "Broot Tree Explorer",
+			BinaryName:    "broot",
+			InstallMethod: "download",
+			DownloadURL:   serverURL + "/broot",
+		},
+		"lazygit": {
+			DisplayName:   "Lazygit Terminal UI",
+			BinaryName:    "lazygit",
+			InstallMethod: "script",
+			InstallScript: "install_scripts/lazygit.sh",
+			Artifacts: []config.Artifact{
+				{Name: "lazygit.tar.gz", URL: serverURL + "/lazygit.tar.gz"},
+			},
+		},
+		"mise": {
+			DisplayName:   "Mise Runtime Manager",
+			BinaryName:    "mise",
+			InstallMethod: "script",
+			InstallScript: "install_scripts/mise.sh",
+		},
+	}
+}
+
+func TestCreate_ShouldFetchAllArtifactsWithManifestAndChecksums(t *testing.T) {
+	server := newArtifactServer(t)
+	dir := t.TempDir()
+	aptExecutor := &fakeAPTExecutor{}
+
+	creator := &Creator{
+		Downloader:      download.NewHTTPDownloader(),
+		CommandExecutor: aptExecutor,
+	}
+
+	manifest, err := creator.Create(dir, []string{"git", "broot", "lazygit", "mise"}, testTools(server.URL))
+	if err != nil {
+		t.Fatalf("Expected bundle creation to succeed, got: %v", err)
+	}
+
+	if len(manifest.Artifacts) != 4 {
+		t.Fatalf("Expected 4 artifacts (deb and dependency, download, script artifact), got %d: %+v", len(manifest.Artifacts), manifest.Artifacts)
+	}
+
+	if len(aptExecutor.ExecutedCommands) != 1 || !strings.Contains(aptExecutor.ExecutedCommands[0], "apt-cache depends --recurse") ||
+		!strings.Contains(aptExecutor.ExecutedCommands[0], "--no-enhances 'git'") {
+		t.Errorf("Expected git to be downloaded with its dependencies, got %v", aptExecutor.ExecutedCommands)
+	}
+
+	if len(manifest.Warnings) != 1 || !strings.Contains(manifest.Warnings[0], "mise") {
+		t.Errorf("Expected a network warning for mise without declared artifacts, got %v", manifest.Warnings)
+	}
+
+	for _, name := range []string{ManifestFile, ChecksumsFile, "downloads/broot/broot", "artifacts/lazygit.tar.gz"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to exist in bundle: %v", name, err)
+		}
+	}
+
+	sums, err := os.ReadFile(filepath.Join(dir, ChecksumsFile))
+	if err != nil {
+		t.Fatalf("Failed to read checksums: %v", err)
+	}
+	if strings.Count(string(sums), "\n") != 4 {
+		t.Errorf("Expected one checksum line per artifact, got:\n%s", sums)
+	}
+}
+
+func TestCreate_ShouldRejectChecksumMismatch(t *testing.T) {
+	server := newArtifactServer(t)
+	tools := testTools(server.URL)
+	broot := tools["broot"]
+	broot.SHA256 = strings.Repeat("0", 64)
+	tools["broot"] = broot
+
+	creator := &Creator{Downloader: download.NewHTTPDownloader(), CommandExecutor: &fakeAPTExecutor{}}
+
+	_, err := creator.Create(t.TempDir(), []string{"broot"}, tools)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected checksum mismatch error, got: %v", err)
+	}
+}
+
+func TestOpen_ShouldServeArtifactsWithoutNetwork(t *testing.T) {
+	server := newArtifactServer(t)
+	dir := t.TempDir()
+	creator := &Creator{Downloader: download.NewHTTPDownloader(), CommandExecutor: &fakeAPTExecutor{}}
+
+	if _, err := creator.Create(dir, []string{"git", "broot"}, testTools(server.URL)); err != nil {
+		t.Fatalf("Expected bundle creation to succeed, got: %v", err)
+	}
+	server.Close() // Everything below must work offline
+
+	b, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Expected bundle to open, got: %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "broot")
+	if err := b.Download(server.URL+"/broot", "", dest); err != nil {
+		t.Fatalf("Expected bundled download to be served, got: %v", err)
+	}
+	content, _ := os.ReadFile(dest)
+	if string(content) != "broot-binary" {
+		t.Errorf("Expected bundled broot content, got %q", content)
+	}
+
+	debs, found := b.DebPaths("git")
+	if !found || len(debs) != 2 || filepath.Base(debs[0]) != "git_1.0_amd64.deb" {
+		t.Errorf("Expected git .deb followed by its dependencies in bundle, got %v", debs)
+	}
+
+	if err := b.Download("https://example.com/not-bundled", "", dest); err == nil {
+		t.Errorf("Expected error for URL not contained in bundle")
+	}
+}
+
+func TestOpen_ShouldDetectTamperedArtifacts(t *testing.T) {
+	server := newArtifactServer(t)
+	dir := t.TempDir()
+	creator := &Creator{Downloader: download.NewHTTPDownloader(), CommandExecutor: &fakeAPTExecutor{}}
+
+	if _, err := creator.Create(dir, []string{"broot"}, testTools(server.URL)); err != nil {
+		t.Fatalf("Expected bundle creation to succeed, got: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "downloads/broot/broot"), []byte("tampered"), 0o644); err != nil {
+		t.Fatalf("Failed to tamper with artifact: %v", err)
+	}
+
+	if _, err := Open(dir); err == nil {
+		t.Errorf("Expected tampered bundle to be rejected")
+	}
+}
+
+// Test that downloads and packages of a steps pipeline are fetched into the bundle
+func TestCreate_ShouldFetchStepDownloadsAndPackages(t *testing.T) {
+	server := newArtifactServer(t)
+	dir := t.TempDir()
+	aptExecutor := &fakeAPTExecutor{}
+	tools := map[string]config.ToolConfig{
+		"lazygit": {
+			DisplayName:   "Lazygit Terminal UI",
+			BinaryName:    "lazygit",
+			InstallMethod: "steps",
+			Steps: []config.Step{
+				{Action: config.StepPackage, Package: "git"},
+				{Action: config.StepDownload, URL: server.URL + "/lazygit.tar.gz"},
+				{Action: config.StepExtract, Src: "lazygit.tar.gz"},
+			},
+		},
+	}
+
+	creator := &Creator{Downloader: download.NewHTTPDownloader(), CommandExecutor: aptExecutor}
+
+	manifest, err := creator.Create(dir, []string{"lazygit"}, tools)
+	if err != nil {
+		t.Fatalf("Expected bundle creation to succeed, got: %v", err)
+	}
+
+	if len(manifest.Artifacts) != 3 {
+		t.Fatalf("Expected a deb with its dependency and a download, got %+v", manifest.Artifacts)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "downloads", "lazygit", "lazygit.tar.gz")); err != nil {
+		t.Errorf("Expected step download in bundle: %v", err)
+	}
+	if len(manifest.Warnings) != 0 {
+		t.Errorf("Expected no network warnings for a steps pipeline, got %v", manifest.Warnings)
+	}
+}
+
+// shellExecutor runs commands with sh, for tests that need real git.
+type shellExecutor struct{}
+
+func (shellExecutor) Execute(command string) error {
+	if output, err := exec.Command("sh", "-c", command).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	return nil
+}
+
+func TestCreate_ShouldBundleGitRepositories(t *testing.T) {
+	// Test that git tools are bundled as a git bundle that can be cloned offline
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	repo := filepath.Join(t.TempDir(), "fzf")
+	for _, args := range [][]string{
+		{"init", "--quiet", repo},
+		{"-C", repo, "-c", "user.name=devenv", "-c", "user.email=devenv@example.com", "commit", "--quiet", "--allow-empty", "-m", "initial"},
+	} {
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+
+	tools := map[string]config.ToolConfig{
+		"fzf": {DisplayName: "fzf", InstallMethod: "git", Git: &config.GitSource{Repo: repo, Dest: "~/.fzf"}},
+	}
+	dir := t.TempDir()
+	manifest, err := (&Creator{CommandExecutor: shellExecutor{}}).Create(dir, []string{"fzf"}, tools)
+	if err != nil {
+		t.Fatalf("Expected bundle creation to succeed, got: %v", err)
+	}
+	if len(manifest.Artifacts) != 1 || manifest.Artifacts[0].Kind != KindRepo || len(manifest.Warnings) != 0 {
+		t.Fatalf("Expected the repository as the only artifact, got %+v (warnings %v)", manifest.Artifacts, manifest.Warnings)
+	}
+
+	b, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Expected bundle to open, got: %v", err)
+	}
+	gitBundle, found := b.RepoPath(repo)
+	if !found {
+		t.Fatalf("Expected bundled repository for %s", repo)
+	}
+	clone := filepath.Join(t.TempDir(), "clone")
+	if output, err := exec.Command("git", "clone", "--quiet", gitBundle, clone).CombinedOutput(); err != nil {
+		t.Errorf("Expected bundled repository to be clonable, got: %v\n%s", err, output)
+	}
+}
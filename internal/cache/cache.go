@@ -0,0 +1,297 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/download"
+)
+
+const (
+	indexFile = "index.json"
+	blobsDir  = "blobs"
+	appDir    = "devenv"
+)
+
+// Entry describes one cached download.
+type Entry struct {
+	Key      string    `json:"key"`
+	URL      string    `json:"url"`
+	Checksum string    `json:"checksum,omitempty"`
+	SHA256   string    `json:"sha256"`
+	Size     int64     `json:"size"`
+	StoredAt time.Time `json:"stored_at"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Cache is a content-addressed store for verified or versioned downloads.
+// Entries are keyed by URL and expected checksum; the content itself is
+// stored once per SHA-256 under blobs/.
+type Cache struct {
+	Dir string
+	Now func() time.Time
+}
+
+func New(dir string) *Cache {
+	return &Cache{Dir: dir, Now: time.Now}
+}
+
+// DefaultDir returns $XDG_CACHE_HOME/devenv, falling back to ~/.cache/devenv.
+func DefaultDir() (string, error) {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, appDir), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".cache", appDir), nil
+}
+
+// Key derives the cache key for a URL and expected checksum.
+func Key(url, checksum string) string {
+	sum := sha256.Sum256([]byte(url + "\x00" + checksum))
+	return hex.EncodeToString(sum[:])
+}
+
+// Lookup returns the blob path for url and checksum if it is cached.
+func (c *Cache) Lookup(url, checksum string) (string, bool) {
+	index, err := c.readIndex()
+	if err != nil {
+		return "", false
+	}
+
+	entry, found := index[Key(url, checksum)]
+	if !found {
+		return "", false
+	}
+
+	blob := c.blobPath(entry.SHA256)
+	if _, err := os.Stat(blob); err != nil {
+		return "", false
+	}
+
+	entry.LastUsed = c.Now()
+	index[entry.Key] = entry
+	_ = c.writeIndex(index)
+
+	return blob, true
+}
+
+// Store moves the file at src into the cache for url and checksum.
+func (c *Cache) Store(url, checksum, src string) (Entry, error) {
+	sum, err := download.FileSHA256(src)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	blob := c.blobPath(sum)
+	if err := os.MkdirAll(filepath.Dir(blob), 0o755); err != nil {
+		return Entry{}, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.Rename(src, blob); err != nil {
+		return Entry{}, fmt.Errorf("failed to store %s in cache: %w", url, err)
+	}
+
+	index, err := c.readIndex()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	now := c.Now()
+	entry := Entry{
+		Key:      Key(url, checksum),
+		URL:      url,
+		Checksum: checksum,
+		SHA256:   sum,
+		Size:     info.Size(),
+		StoredAt: now,
+		LastUsed: now,
+	}
+	index[entry.Key] = entry
+
+	return entry, c.writeIndex(index)
+}
+
+// List returns all cache entries, most recently used first.
+func (c *Cache) List() ([]Entry, error) {
+	index, err := c.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(index))
+	for _, entry := range index {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+
+	return entries, nil
+}
+
+// Prune removes entries not used within maxAge, entries whose content is
+// missing, and blobs no entry refers to. It returns the number of removed
+// entries and the bytes freed.
+func (c *Cache) Prune(maxAge time.Duration) (int, int64, error) {
+	index, err := c.readIndex()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cutoff := c.Now().Add(-maxAge)
+	removed := 0
+	for key, entry := range index {
+		_, statErr := os.Stat(c.blobPath(entry.SHA256))
+		if entry.LastUsed.Before(cutoff) || statErr != nil {
+			delete(index, key)
+			removed++
+		}
+	}
+
+	if err := c.writeIndex(index); err != nil {
+		return removed, 0, err
+	}
+
+	referenced := make(map[string]bool)
+	for _, entry := range index {
+		referenced[entry.SHA256] = true
+	}
+
+	blobs, err := os.ReadDir(filepath.Join(c.Dir, blobsDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return removed, 0, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	var freed int64
+	for _, blob := range blobs {
+		if referenced[blob.Name()] {
+			continue
+		}
+		if info, err := blob.Info(); err == nil {
+			freed += info.Size()
+		}
+		if err := os.Remove(c.blobPath(blob.Name())); err != nil {
+			return removed, freed, fmt.Errorf("failed to remove cached blob: %w", err)
+		}
+	}
+
+	return removed, freed, nil
+}
+
+// Clear removes the whole cache directory.
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) blobPath(sum string) string {
+	return filepath.Join(c.Dir, blobsDir, sum)
+}
+
+func (c *Cache) readIndex() (map[string]Entry, error) {
+	index := make(map[string]Entry)
+
+	data, err := os.ReadFile(filepath.Join(c.Dir, indexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache index: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse cache index: %w", err)
+	}
+	return index, nil
+}
+
+func (c *Cache) writeIndex(index map[string]Entry) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache index: %w", err)
+	}
+
+	tmp := filepath.Join(c.Dir, indexFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache index: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(c.Dir, indexFile))
+}
+
+// versionedURL matches URLs that name a release version in their path, such
+// as https://github.com/o/r/releases/download/v0.44.1/asset.tar.gz.
+var versionedURL = regexp.MustCompile(`/v?[0-9]+\.[0-9]+(\.[0-9]+)*/`)
+
+// Cacheable reports whether a download of url can be served from the cache:
+// either its checksum is known or the URL is pinned to a release version.
+// Other URLs, such as a "latest" asset, can change and are always fetched.
+func Cacheable(url, checksum string) bool {
+	return checksum != "" || versionedURL.MatchString(url)
+}
+
+// Downloader serves downloads from the cache and fills it on a miss.
+// Downloads that are not Cacheable bypass the cache.
+type Downloader struct {
+	Cache      *Cache
+	Downloader download.Downloader
+}
+
+func (d *Downloader) Download(url, expectedSHA256, dest string) error {
+	if !Cacheable(url, expectedSHA256) {
+		return d.Downloader.Download(url, expectedSHA256, dest)
+	}
+
+	if blob, found := d.Cache.Lookup(url, expectedSHA256); found {
+		if err := download.CopyFile(blob, dest); err != nil {
+			return err
+		}
+		return download.VerifySHA256(dest, expectedSHA256)
+	}
+
+	if err := os.MkdirAll(d.Cache.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(d.Cache.Dir, ".fetch-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := d.Downloader.Download(url, expectedSHA256, tmp.Name()); err != nil {
+		return err
+	}
+
+	if _, err := d.Cache.Store(url, expectedSHA256, tmp.Name()); err != nil {
+		return err
+	}
+
+	blob, found := d.Cache.Lookup(url, expectedSHA256)
+	if !found {
+		return fmt.Errorf("failed to read %s back from cache", url)
+	}
+	return download.CopyFile(blob, dest)
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/download"
+)
+
+func newCountingServer(t *testing.T, body string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestDownloader_ShouldReuseCachedArtifactAcrossRuns(t *testing.T) {
+	server, requests := newCountingServer(t, "nvim-archive")
+	archiveSHA256 := "8a2d432348e24bb3a3a26f8f3bcefce25f6c96e60dd4011e7ce4a5ece865582c"
+	cacheDir := t.TempDir()
+
+	for run := 0; run < 2; run++ {
+		// A fresh downloader per run simulates separate devenv invocations
+		downloader := &Downloader{Cache: New(cacheDir), Downloader: download.NewHTTPDownloader()}
+		dest := filepath.Join(t.TempDir(), "nvim.tar.gz")
+
+		if err := downloader.Download(server.URL+"/nvim.tar.gz", archiveSHA256, dest); err != nil {
+			t.Fatalf("Run %d: expected download to succeed, got: %v", run, err)
+		}
+
+		content, _ := os.ReadFile(dest)
+		if string(content) != "nvim-archive" {
+			t.Errorf("Run %d: expected artifact content, got %q", run, content)
+		}
+	}
+
+	if *requests != 1 {
+		t.Errorf("Expected a single network request across runs, got %d", *requests)
+	}
+}
+
+func TestDownloader_ShouldStoreIdenticalContentOnce(t *testing.T) {
+	server, requests := newCountingServer(t, "hello")
+	helloSHA256 := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	downloader := &Downloader{Cache: New(t.TempDir()), Downloader: download.NewHTTPDownloader()}
+	dest := filepath.Join(t.TempDir(), "file")
+
+	for _, url := range []string{server.URL + "/file", server.URL + "/mirror/file"} {
+		if err := downloader.Download(url, helloSHA256, dest); err != nil {
+			t.Fatalf("Expected download of %s to succeed, got: %v", url, err)
+		}
+	}
+
+	if *requests != 2 {
+		t.Errorf("Expected a different URL to miss the cache, got %d requests", *requests)
+	}
+
+	entries, err := downloader.Cache.List()
+	if err != nil {
+		t.Fatalf("Expected cache list to succeed, got: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 entries for the two URLs, got %d", len(entries))
+	}
+
+	blobs, _ := os.ReadDir(filepath.Join(downloader.Cache.Dir, blobsDir))
+	if len(blobs) != 1 {
+		t.Errorf("Expected identical content to be stored once, got %d blobs", len(blobs))
+	}
+}
+
+func TestDownloader_ShouldNotCacheUnverifiedDownloads(t *testing.T) {
+	// Test that downloads without a checksum are fetched every time, so a moving URL is never served stale
+	server, requests := newCountingServer(t, "latest")
+	downloader := &Downloader{Cache: New(t.TempDir()), Downloader: download.NewHTTPDownloader()}
+	dest := filepath.Join(t.TempDir(), "file")
+
+	for run := 0; run < 2; run++ {
+		if err := downloader.Download(server.URL+"/latest", "", dest); err != nil {
+			t.Fatalf("Run %d: expected download to succeed, got: %v", run, err)
+		}
+	}
+
+	if *requests != 2 {
+		t.Errorf("Expected every unverified download to hit the network, got %d requests", *requests)
+	}
+	if entries, _ := downloader.Cache.List(); len(entries) != 0 {
+		t.Errorf("Expected no cache entry without a checksum, got %d", len(entries))
+	}
+}
+
+func TestDownloader_ShouldNotCacheChecksumMismatch(t *testing.T) {
+	server, _ := newCountingServer(t, "tampered")
+	downloader := &Downloader{Cache: New(t.TempDir()), Downloader: download.NewHTTPDownloader()}
+
+	err := downloader.Download(server.URL+"/file", strings.Repeat("0", 64), filepath.Join(t.TempDir(), "file"))
+	if err == nil {
+		t.Fatalf("Expected checksum mismatch error")
+	}
+
+	entries, _ := downloader.Cache.List()
+	if len(entries) != 0 {
+		t.Errorf("Expected no cache entry for rejected download, got %d", len(entries))
+	}
+}
+
+func TestPrune_ShouldRemoveStaleEntriesAndOrphanBlobs(t *testing.T) {
+	c := New(t.TempDir())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, url := range []string{"https://example.com/old", "https://example.com/new"} {
+		src := filepath.Join(t.TempDir(), "artifact")
+		if err := os.WriteFile(src, []byte(url), 0o644); err != nil {
+			t.Fatalf("Failed to write artifact: %v", err)
+		}
+
+		c.Now = func() time.Time { return now.Add(time.Duration(i) * 60 * 24 * time.Hour) }
+		if _, err := c.Store(url, "", src); err != nil {
+			t.Fatalf("Expected store to succeed, got: %v", err)
+		}
+	}
+
+	c.Now = func() time.Time { return now.Add(70 * 24 * time.Hour) }
+	removed, freed, err := c.Prune(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Expected prune to succeed, got: %v", err)
+	}
+
+	if removed != 1 || freed == 0 {
+		t.Errorf("Expected 1 stale entry removed with bytes freed, got removed=%d freed=%d", removed, freed)
+	}
+
+	if _, found := c.Lookup("https://example.com/old", ""); found {
+		t.Errorf("Expected stale entry to be pruned")
+	}
+	if _, found := c.Lookup("https://example.com/new", ""); !found {
+		t.Errorf("Expected recent entry to be kept")
+	}
+}
+
+func TestClear_ShouldRemoveEverything(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "devenv"))
+	src := filepath.Join(t.TempDir(), "artifact")
+	if err := os.WriteFile(src, []byte("content"), 0o644); err != nil {
+		t.Fatalf("Failed to write artifact: %v", err)
+	}
+	if _, err := c.Store("https://example.com/a", "", src); err != nil {
+		t.Fatalf("Expected store to succeed, got: %v", err)
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Expected clear to succeed, got: %v", err)
+	}
+
+	entries, err := c.List()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected empty cache after clear, got %d entries (err: %v)", len(entries), err)
+	}
+}
+
+func TestDefaultDir_ShouldHonourXDGCacheHome(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg-cache")
+
+	dir, err := DefaultDir()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if dir != "/tmp/xdg-cache/devenv" {
+		t.Errorf("Expected /tmp/xdg-cache/devenv, got %s", dir)
+	}
+}
+
+func TestCacheable_ShouldAcceptChecksumsAndVersionedURLs(t *testing.T) {
+	// Test that only verified or release-pinned downloads are cached
+	tests := map[string]bool{
+		"https://github.com/jesseduffield/lazygit/releases/download/v0.44.1/lazygit_0.44.1_Linux_x86_64.tar.gz": true,
+		"https://github.com/sharkdp/bat/releases/download/v0.24.0/bat-v0.24.0-x86_64-unknown-linux-gnu.tar.gz":  true,
+		"https://dystroy.org/broot/download/x86_64-unknown-linux-gnu/broot":                                     false,
+		"https://github.com/neovim/neovim/releases/download/stable/nvim-linux-x86_64.tar.gz":                    false,
+	}
+	for url, want := range tests {
+		if got := Cacheable(url, ""); got != want {
+			t.Errorf("Expected Cacheable(%s) = %v, got %v", url, want, got)
+		}
+	}
+	if !Cacheable("https://example.com/latest/tool", strings.Repeat("0", 64)) {
+		t.Errorf("Expected downloads with a checksum to be cacheable")
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	archPlaceholder    = "{arch}"
+	versionPlaceholder = "{version}"
+)
+
+// ErrUnsupportedArchitecture is returned for tools that are not available for
+// the detected CPU architecture.
+var ErrUnsupportedArchitecture = errors.New("unsupported on this architecture")
+
+// DefaultArchNames maps Go/Debian architecture names to the names most release
+// assets use. A tool's arch_map overrides these for {arch} placeholders.
+var DefaultArchNames = map[string]string{
+	"amd64": "x86_64",
+	"arm64": "aarch64",
+}
+
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+}
+
+// NormalizeArch converts uname style names (x86_64, aarch64) to the Go/Debian
+// names (amd64, arm64) used throughout the configuration.
+func NormalizeArch(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if normalized, found := archAliases[arch]; found {
+		return normalized
+	}
+	return arch
+}
+
+// SupportsArchitecture reports whether tool can be installed on arch. Tools
+// without an architectures list or per-architecture URLs support any. Tools
+// with a fallback chain are not limited by their download URLs, since
+// another method may still apply.
+func SupportsArchitecture(tool ToolConfig, arch string) bool {
+	arch = NormalizeArch(arch)
+
+	if len(tool.Architectures) > 0 && !slices.Contains(tool.Architectures, arch) {
+		return false
+	}
+
+	if len(tool.DownloadURLs) > 0 && len(tool.InstallMethods) == 0 {
+		_, found := tool.DownloadURLs[arch]
+		return found
+	}
+
+	return true
+}
+
+// ExpandArch replaces {arch} in value with the tool's name for arch.
+func ExpandArch(tool ToolConfig, value, arch string) (string, error) {
+	if !strings.Contains(value, archPlaceholder) {
+		return value, nil
+	}
+
+	arch = NormalizeArch(arch)
+	name, found := tool.ArchMap[arch]
+	if !found {
+		name, found = DefaultArchNames[arch]
+	}
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedArchitecture, arch)
+	}
+
+	return strings.ReplaceAll(value, archPlaceholder, name), nil
+}
+
+// ResolveDownloadURL returns the download URL of tool for arch, preferring
+// download_urls over a download_url with {arch} placeholders.
+func ResolveDownloadURL(tool ToolConfig, arch string) (string, error) {
+	arch = NormalizeArch(arch)
+
+	if !SupportsArchitecture(tool, arch) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedArchitecture, arch)
+	}
+
+	if url, found := tool.DownloadURLs[arch]; found {
+		return url, nil
+	}
+	if len(tool.DownloadURLs) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedArchitecture, arch)
+	}
+
+	return ExpandArch(tool, tool.DownloadURL, arch)
+}
+
+// ResolveChecksum returns the expected checksum of the download for arch.
+func ResolveChecksum(tool ToolConfig, arch string) string {
+	if checksum, found := tool.Checksums[NormalizeArch(arch)]; found {
+		return checksum
+	}
+	return tool.SHA256
+}
+
+// ExpandStepValue replaces {version} and {arch} in a step value.
+func ExpandStepValue(tool ToolConfig, value, arch string) (string, error) {
+	value = strings.ReplaceAll(value, versionPlaceholder, tool.Version)
+	return ExpandArch(tool, value, arch)
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeArch_ShouldMapUnameNames(t *testing.T) {
+	cases := map[string]string{
+		"x86_64":  "amd64",
+		"aarch64": "arm64",
+		"amd64":   "amd64",
+		"arm64":   "arm64",
+		"riscv64": "riscv64",
+	}
+
+	for input, expected := range cases {
+		if actual := NormalizeArch(input); actual != expected {
+			t.Errorf("NormalizeArch(%q): expected %q, got %q", input, expected, actual)
+		}
+	}
+}
+
+func TestResolveDownloadURL_ShouldPreferPerArchitectureURLs(t *testing.T) {
+	tool := ToolConfig{
+		DownloadURLs: map[string]string{
+			"amd64": "https://example.com/x86_64/broot",
+			"arm64": "https://example.com/aarch64/broot",
+		},
+	}
+
+	url, err := ResolveDownloadURL(tool, "aarch64")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if url != "https://example.com/aarch64/broot" {
+		t.Errorf("Expected arm64 URL, got %s", url)
+	}
+
+	_, err = ResolveDownloadURL(tool, "riscv64")
+	if !errors.Is(err, ErrUnsupportedArchitecture) {
+		t.Errorf("Expected ErrUnsupportedArchitecture for missing architecture, got: %v", err)
+	}
+}
+
+func TestResolveDownloadURL_ShouldExpandArchPlaceholder(t *testing.T) {
+	tool := ToolConfig{
+		DownloadURL: "https://example.com/lazygit_Linux_{arch}.tar.gz",
+	}
+
+	url, err := ResolveDownloadURL(tool, "amd64")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if url != "https://example.com/lazygit_Linux_x86_64.tar.gz" {
+		t.Errorf("Expected default x86_64 mapping, got %s", url)
+	}
+
+	tool.ArchMap = map[string]string{"arm64": "arm64"}
+	url, err = ResolveDownloadURL(tool, "arm64")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if url != "https://example.com/lazygit_Linux_arm64.tar.gz" {
+		t.Errorf("Expected arch_map to override default mapping, got %s", url)
+	}
+}
+
+func TestSupportsArchitecture_ShouldHonourArchitecturesList(t *testing.T) {
+	tool := ToolConfig{Architectures: []string{"amd64"}}
+
+	if !SupportsArchitecture(tool, "x86_64") {
+		t.Errorf("Expected amd64-only tool to support x86_64")
+	}
+	if SupportsArchitecture(tool, "arm64") {
+		t.Errorf("Expected amd64-only tool not to support arm64")
+	}
+	if !SupportsArchitecture(ToolConfig{}, "arm64") {
+		t.Errorf("Expected tools without restrictions to support any architecture")
+	}
+}
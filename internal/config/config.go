@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,6 +15,7 @@ type ToolConfig struct {
 	DisplayName      string            `yaml:"display_name"`
 	BinaryName       string            `yaml:"binary_name"`
 	InstallMethod    string            `yaml:"install_method"`
+	InstallMethods   []string          `yaml:"install_methods,omitempty"`
 	PackageName      string            `yaml:"package_name"`
 	InstallScript    string            `yaml:"install_script"`
 	ConfigPath       string            `yaml:"config_path"`
@@ -19,21 +24,130 @@ type ToolConfig struct {
 	WSLNotes         string            `yaml:"wsl_notes"`
 	Version          string            `yaml:"version,omitempty"`
 	DownloadURL      string            `yaml:"download_url,omitempty"`
+	DownloadURLs     map[string]string `yaml:"download_urls,omitempty"`
+	SHA256           string            `yaml:"sha256,omitempty"`
+	Checksums        map[string]string `yaml:"checksums,omitempty"`
+	ArchMap          map[string]string `yaml:"arch_map,omitempty"`
+	Architectures    []string          `yaml:"architectures,omitempty"`
 	PostInstallSteps []string          `yaml:"post_install_steps,omitempty"`
+	PostInstall      []string          `yaml:"post_install,omitempty"`
 	ValidateCommand  string            `yaml:"validate_command,omitempty"`
 	EnvVars          map[string]string `yaml:"env_vars,omitempty"`
 	InstallLocation  string            `yaml:"install_location,omitempty"`
 	RequiredPackages []string          `yaml:"required_packages,omitempty"`
 	CheckCommand     string            `yaml:"check_command,omitempty"`
+	Artifacts        []Artifact        `yaml:"artifacts,omitempty"`
+	AptRepository    *AptRepository    `yaml:"apt_repository,omitempty"`
+	Git              *GitSource        `yaml:"git,omitempty"`
+	Steps            []Step            `yaml:"steps,omitempty"`
+	Retries          int               `yaml:"retries,omitempty"`
+	RetryDelay       time.Duration     `yaml:"retry_delay,omitempty"`
+}
+
+// Artifact is remote content an install script fetches at run time, declared
+// so it can be prefetched into an offline bundle.
+type Artifact struct {
+	Name   string `yaml:"name"`
+	URL    string `yaml:"url"`
+	SHA256 string `yaml:"sha256,omitempty"`
+}
+
+// AptRepository is a third-party apt repository a tool's packages come from.
+// Suite may contain {codename}, which is replaced by the distribution's
+// VERSION_CODENAME.
+type AptRepository struct {
+	Name          string   `yaml:"name"`
+	URL           string   `yaml:"url"`
+	KeyURL        string   `yaml:"key_url"`
+	Fingerprint   string   `yaml:"fingerprint,omitempty"`
+	Suite         string   `yaml:"suite"`
+	Components    []string `yaml:"components,omitempty"`
+	Architectures []string `yaml:"arch,omitempty"`
+}
+
+// GitSource is a repository cloned by the git install method. Ref may be a
+// branch, tag or commit; without it the remote's default branch is used.
+// PostClone runs inside Dest after every clone or update.
+type GitSource struct {
+	Repo      string `yaml:"repo"`
+	Ref       string `yaml:"ref,omitempty"`
+	Dest      string `yaml:"dest"`
+	PostClone string `yaml:"post_clone,omitempty"`
+}
+
+// Step actions of a steps pipeline.
+const (
+	StepPackage  = "package"
+	StepDownload = "download"
+	StepExtract  = "extract"
+	StepCopy     = "copy"
+	StepSymlink  = "symlink"
+	StepMkdir    = "mkdir"
+	StepRun      = "run"
+	StepVerify   = "verify"
+)
+
+// Step is one action of the steps install method. The fields used depend on
+// Action:
+//
+//	package   Package (apt package names)
+//	download  URL, SHA256, Dest (defaults to the URL's file name)
+//	extract   Src archive into Dest, optionally only Files
+//	copy      Src to Dest with Mode (defaults to 0755)
+//	symlink   Dest pointing at Src
+//	mkdir     Dest
+//	run       Command, with Undo reverting it on uninstall
+//	verify    Command, which must succeed
+//
+// Relative paths refer to a scratch directory shared by the steps of one
+// install. Values may contain {arch}, {version} and {prefix}.
+type Step struct {
+	Action  string   `yaml:"action"`
+	Package string   `yaml:"package,omitempty"`
+	URL     string   `yaml:"url,omitempty"`
+	SHA256  string   `yaml:"sha256,omitempty"`
+	Src     string   `yaml:"src,omitempty"`
+	Dest    string   `yaml:"dest,omitempty"`
+	Files   []string `yaml:"files,omitempty"`
+	Mode    string   `yaml:"mode,omitempty"`
+	Command string   `yaml:"command,omitempty"`
+	Undo    string   `yaml:"undo,omitempty"`
+}
+
+// Methods returns the install methods to try for tool, in order. An
+// install_methods list takes precedence over install_method.
+func Methods(tool ToolConfig) []string {
+	if len(tool.InstallMethods) > 0 {
+		return tool.InstallMethods
+	}
+	return []string{tool.InstallMethod}
 }
 
 type CategoryConfig map[string]ToolConfig
 
+// scriptLibrary is the helper library sourced into every install script,
+// relative to the config file's directory.
+const scriptLibrary = "install_scripts/lib/devenv.sh"
+
 type Config struct {
 	Categories map[string]CategoryConfig `yaml:"categories"`
+
+	// ScriptLibrary is the resolved helper library for install scripts. It
+	// is only set when the config has tools with an install script.
+	ScriptLibrary string `yaml:"-"`
 }
 
+// LoadConfig reads the config at filePath. Relative install scripts, config
+// templates and the script helper library are resolved against the config
+// file's directory, and every referenced file must exist.
 func LoadConfig(filePath string) (Config, error) {
+	return LoadConfigWithFallback(filePath, nil)
+}
+
+// LoadConfigWithFallback is LoadConfig, except that relative paths missing
+// next to the config are kept as they are when fallback reports them
+// available elsewhere, e.g. embedded in the binary.
+func LoadConfigWithFallback(filePath string, fallback func(name string) bool) (Config, error) {
 	file, err := os.ReadFile(filePath)
 	if err != nil {
 		return Config{}, err
@@ -45,9 +159,77 @@ func LoadConfig(filePath string) (Config, error) {
 		return Config{}, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := cfg.resolvePaths(filepath.Dir(filePath), fallback); err != nil {
+		return Config{}, err
+	}
+
 	return cfg, nil
 }
 
+// resolvePaths rewrites relative file references of every tool to paths
+// under dir and reports all that do not exist.
+func (c *Config) resolvePaths(dir string, fallback func(name string) bool) error {
+	var missing []error
+	usesScripts := false
+	for _, category := range sortedKeys(c.Categories) {
+		tools := c.Categories[category]
+		for _, toolName := range sortedKeys(tools) {
+			tool := tools[toolName]
+
+			var err error
+			if tool.InstallScript, err = resolvePath(dir, tool.InstallScript, fallback); err != nil {
+				missing = append(missing, fmt.Errorf("%s: install_script %w", toolName, err))
+			}
+			usesScripts = usesScripts || tool.InstallScript != ""
+			if tool.ConfigTemplate, err = resolvePath(dir, tool.ConfigTemplate, fallback); err != nil {
+				missing = append(missing, fmt.Errorf("%s: config_template %w", toolName, err))
+			}
+
+			tools[toolName] = tool
+		}
+	}
+
+	if usesScripts {
+		var err error
+		if c.ScriptLibrary, err = resolvePath(dir, scriptLibrary, fallback); err != nil {
+			missing = append(missing, fmt.Errorf("script library %w", err))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(missing...))
+	}
+	return nil
+}
+
+func resolvePath(dir, path string, fallback func(name string) bool) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	resolved := path
+	if !filepath.IsAbs(path) {
+		resolved = filepath.Join(dir, path)
+	}
+
+	if _, err := os.Stat(resolved); err == nil {
+		return resolved, nil
+	}
+	if !filepath.IsAbs(path) && fallback != nil && fallback(path) {
+		return path, nil
+	}
+	return path, fmt.Errorf("%s not found", resolved)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func GetCategories(cfg Config) []string {
 	categories := make([]string, 0, len(cfg.Categories))
 	for category := range cfg.Categories {
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_ShouldLoadValidYAMLFile(t *testing.T) {
@@ -40,3 +44,101 @@ func TestLoadConfig_ShouldLoadValidYAMLFile(t *testing.T) {
 		t.Errorf("Expected install_method 'manual', got '%s'", alacritty.InstallMethod)
 	}
 }
+
+func TestLoadConfig_ShouldParseRetrySettings(t *testing.T) {
+	config, err := LoadConfig("../../config.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var githubCLI ToolConfig
+	for _, category := range config.Categories {
+		if tool, exists := category["github_cli"]; exists {
+			githubCLI = tool
+		}
+	}
+
+	if githubCLI.Retries != 2 {
+		t.Errorf("Expected retries 2, got %d", githubCLI.Retries)
+	}
+
+	if githubCLI.RetryDelay != 5*time.Second {
+		t.Errorf("Expected retry_delay 5s, got %v", githubCLI.RetryDelay)
+	}
+}
+
+const pathsConfig = `categories:
+  shell:
+    zsh:
+      display_name: "Zsh"
+      binary_name: "zsh"
+      install_method: "script"
+      install_script: "install_scripts/zsh.sh"
+      config_template: "templates/zsh.conf"
+`
+
+func writeConfigDir(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(pathsConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadConfig_ShouldResolvePathsAgainstConfigDirectory(t *testing.T) {
+	dir := writeConfigDir(t, "install_scripts/zsh.sh", "install_scripts/lib/devenv.sh", "templates/zsh.conf")
+
+	config, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	zsh := config.Categories["shell"]["zsh"]
+	if zsh.InstallScript != filepath.Join(dir, "install_scripts", "zsh.sh") {
+		t.Errorf("Expected install script next to the config, got %s", zsh.InstallScript)
+	}
+	if zsh.ConfigTemplate != filepath.Join(dir, "templates", "zsh.conf") {
+		t.Errorf("Expected config template next to the config, got %s", zsh.ConfigTemplate)
+	}
+	if config.ScriptLibrary != filepath.Join(dir, "install_scripts", "lib", "devenv.sh") {
+		t.Errorf("Expected script library next to the config, got %s", config.ScriptLibrary)
+	}
+}
+
+func TestLoadConfig_ShouldRejectMissingFiles(t *testing.T) {
+	dir := writeConfigDir(t, "install_scripts/zsh.sh")
+
+	_, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "zsh: config_template") {
+		t.Errorf("Expected missing config template to be reported, got: %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "script library") {
+		t.Errorf("Expected missing script library to be reported, got: %v", err)
+	}
+}
+
+func TestLoadConfigWithFallback_ShouldKeepPathsAvailableElsewhere(t *testing.T) {
+	dir := writeConfigDir(t)
+	embedded := func(name string) bool {
+		return name == "install_scripts/zsh.sh" || name == "install_scripts/lib/devenv.sh" || name == "templates/zsh.conf"
+	}
+
+	config, err := LoadConfigWithFallback(filepath.Join(dir, "config.yaml"), embedded)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if zsh := config.Categories["shell"]["zsh"]; zsh.InstallScript != "install_scripts/zsh.sh" {
+		t.Errorf("Expected embedded script to keep its relative path, got %s", zsh.InstallScript)
+	}
+}
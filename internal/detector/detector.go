@@ -1,8 +1,11 @@
 package detector
 
 import (
+	"log/slog"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/petersenjoern/devenv/internal/config"
@@ -15,16 +18,35 @@ type Status struct {
 	Path            string
 }
 
-type Detector struct{}
+// ScriptProbe asks a tool's install script whether the tool is installed
+// and which version it has. Both return an error when the script does not
+// implement the question, in which case the binary is looked up instead.
+type ScriptProbe interface {
+	Check(tool config.ToolConfig) (bool, error)
+	Version(tool config.ToolConfig) (string, error)
+}
+
+type Detector struct {
+	// Scripts, when set, is asked first about tools installed by scripts.
+	Scripts ScriptProbe
+	// Logger receives how each tool was detected; defaults to
+	// slog.Default().
+	Logger *slog.Logger
+}
 
 func New() *Detector {
 	return &Detector{}
 }
 
 func (d *Detector) DetectTool(tool config.ToolConfig) Status {
+	if status, ok := d.detectWithScript(tool); ok {
+		return status
+	}
+
 	path, err := exec.LookPath(tool.BinaryName)
 
 	if err != nil {
+		d.log().Debug("binary not found", "tool", tool.DisplayName, "binary", tool.BinaryName)
 		return Status{
 			BinaryInstalled: false,
 			ConfigApplied:   false,
@@ -33,18 +55,92 @@ func (d *Detector) DetectTool(tool config.ToolConfig) Status {
 		}
 	}
 
-	return Status{
+	status := Status{
 		BinaryInstalled: true,
 		ConfigApplied:   d.IsConfigExisting(tool.ConfigPath),
 		Version:         d.GetVersion(tool.BinaryName),
 		Path:            path,
 	}
+	d.log().Debug("binary found", "tool", tool.DisplayName, "path", path, "version", status.Version)
+	return status
+}
+
+func (d *Detector) log() *slog.Logger {
+	if d.Logger != nil {
+		return d.Logger
+	}
+	return slog.Default()
+}
+
+// detectWithScript uses the script's check and version verbs. It reports
+// false when the script cannot answer, e.g. legacy scripts without verbs.
+func (d *Detector) detectWithScript(tool config.ToolConfig) (Status, bool) {
+	if d.Scripts == nil || tool.InstallMethod != "script" {
+		return Status{}, false
+	}
+
+	installed, err := d.Scripts.Check(tool)
+	if err != nil {
+		d.log().Debug("script check unavailable, looking up binary", "tool", tool.DisplayName, "error", err)
+		return Status{}, false
+	}
+	d.log().Debug("script check", "tool", tool.DisplayName, "installed", installed)
+	if !installed {
+		return Status{}, true
+	}
+
+	version, err := d.Scripts.Version(tool)
+	if err != nil {
+		d.log().Debug("script version unavailable", "tool", tool.DisplayName, "error", err)
+		version = d.GetVersion(tool.BinaryName)
+	}
+	path, _ := exec.LookPath(tool.BinaryName)
+
+	return Status{
+		BinaryInstalled: true,
+		ConfigApplied:   d.IsConfigExisting(tool.ConfigPath),
+		Version:         version,
+		Path:            path,
+	}, true
 }
 
 func (d *Detector) DetectEnvironment() (string, error) {
 	return "linux", nil
 }
 
+// DetectArchitecture returns the CPU architecture of the machine using
+// Go/Debian names (amd64, arm64), which is what download_urls and arch_map
+// are keyed by. It asks the kernel rather than the build target, so an amd64
+// binary emulated on arm64 still reports arm64.
+func (d *Detector) DetectArchitecture() string {
+	output, err := exec.Command("uname", "-m").Output()
+	if err != nil || strings.TrimSpace(string(output)) == "" {
+		d.log().Debug("uname failed, using build architecture", "arch", runtime.GOARCH, "error", err)
+		return config.NormalizeArch(runtime.GOARCH)
+	}
+	return config.NormalizeArch(string(output))
+}
+
+// UserPrefix returns ~/.local, the prefix used for rootless installs.
+func (d *Detector) UserPrefix() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local"), nil
+}
+
+// IsDirOnPath reports whether dir is one of the entries in $PATH.
+func (d *Detector) IsDirOnPath(dir string) bool {
+	dir = filepath.Clean(dir)
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if entry != "" && filepath.Clean(entry) == dir {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *Detector) IsBinaryInstalled(binaryName string) bool {
 	_, err := exec.LookPath(binaryName)
 	return err == nil
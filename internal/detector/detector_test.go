@@ -1,6 +1,8 @@
 package detector
 
 import (
+	"errors"
+	"os/exec"
 	"testing"
 
 	"github.com/petersenjoern/devenv/internal/config"
@@ -145,3 +147,76 @@ func TestDetectTool_ShouldReturnFalseConfigWhenFileDoesNotExist(t *testing.T) {
 		t.Errorf("Expected ConfigApplied to be false when config file doesn't exist, got true")
 	}
 }
+
+func TestDetectArchitecture_ShouldReturnNormalizedName(t *testing.T) {
+	detector := New()
+
+	arch := detector.DetectArchitecture()
+
+	if arch == "" || arch == "x86_64" || arch == "aarch64" {
+		t.Errorf("Expected Go/Debian style architecture name, got '%s'", arch)
+	}
+}
+
+func TestDetectArchitecture_ShouldReportMachineArchitecture(t *testing.T) {
+	// Test that the architecture comes from the kernel, not the build target
+	machine, err := exec.Command("uname", "-m").Output()
+	if err != nil {
+		t.Skipf("uname not available: %v", err)
+	}
+
+	if arch, want := New().DetectArchitecture(), config.NormalizeArch(string(machine)); arch != want {
+		t.Errorf("Expected %s from uname -m, got %s", want, arch)
+	}
+}
+
+func TestIsDirOnPath_ShouldMatchPathEntries(t *testing.T) {
+	detector := New()
+	t.Setenv("PATH", "/usr/bin:/home/dev/.local/bin/:/bin")
+
+	if !detector.IsDirOnPath("/home/dev/.local/bin") {
+		t.Errorf("Expected ~/.local/bin to be found on PATH")
+	}
+
+	if detector.IsDirOnPath("/opt/bin") {
+		t.Errorf("Expected /opt/bin not to be found on PATH")
+	}
+}
+
+type stubScriptProbe struct {
+	installed bool
+	version   string
+	err       error
+}
+
+func (s *stubScriptProbe) Check(tool config.ToolConfig) (bool, error) {
+	return s.installed, s.err
+}
+
+func (s *stubScriptProbe) Version(tool config.ToolConfig) (string, error) {
+	return s.version, s.err
+}
+
+func TestDetectTool_ShouldAskScriptsWithCheckVerb(t *testing.T) {
+	// Test that script tools are detected through their check and version verbs
+	detector := &Detector{Scripts: &stubScriptProbe{installed: true, version: "mise 2024.1.0"}}
+	tool := config.ToolConfig{BinaryName: "not-on-path-binary", InstallMethod: "script"}
+
+	status := detector.DetectTool(tool)
+
+	if !status.BinaryInstalled || status.Version != "mise 2024.1.0" {
+		t.Errorf("Expected script to report the tool installed with its version, got %+v", status)
+	}
+}
+
+func TestDetectTool_ShouldFallBackToBinaryForLegacyScripts(t *testing.T) {
+	// Test that scripts without a check verb fall back to looking up the binary
+	detector := &Detector{Scripts: &stubScriptProbe{installed: true, err: errors.New("verb not supported")}}
+	tool := config.ToolConfig{BinaryName: "not-on-path-binary", InstallMethod: "script"}
+
+	status := detector.DetectTool(tool)
+
+	if status.BinaryInstalled {
+		t.Errorf("Expected binary lookup to decide, got %+v", status)
+	}
+}
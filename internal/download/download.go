@@ -0,0 +1,137 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 10 * time.Minute
+
+// ErrChecksumMismatch marks downloads whose content does not match the
+// expected checksum. Downloading again will not fix it, so it is not retried.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Downloader fetches the content behind a URL into a local file. When
+// expectedSHA256 is set, implementations must reject content that does not
+// match it.
+type Downloader interface {
+	Download(url, expectedSHA256, dest string) error
+}
+
+// HTTPDownloader downloads over HTTP(S).
+type HTTPDownloader struct {
+	Client *http.Client
+}
+
+func NewHTTPDownloader() *HTTPDownloader {
+	return &HTTPDownloader{
+		Client: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+func (h *HTTPDownloader) Download(url, expectedSHA256, dest string) error {
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: unexpected status %s", url, resp.Status)
+	}
+
+	if err := WriteFile(dest, resp.Body); err != nil {
+		return err
+	}
+
+	if err := VerifySHA256(dest, expectedSHA256); err != nil {
+		os.Remove(dest)
+		return err
+	}
+
+	return nil
+}
+
+// WriteFile atomically writes the content of r to dest, creating parent
+// directories as needed.
+func WriteFile(dest string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dest, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".download-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", dest, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+
+	return os.Rename(tmp.Name(), dest)
+}
+
+// CopyFile copies src to dest via WriteFile.
+func CopyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return WriteFile(dest, in)
+}
+
+// FileSHA256 returns the hex encoded SHA-256 checksum of the file at path.
+func FileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// VerifySHA256 checks the file at path against the expected checksum. An
+// empty expected checksum always passes.
+func VerifySHA256(path, expected string) error {
+	if expected == "" {
+		return nil
+	}
+
+	actual, err := FileSHA256(path)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, filepath.Base(path), expected, actual)
+	}
+
+	return nil
+}
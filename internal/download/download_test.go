@@ -0,0 +1,64 @@
+package download
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHTTPDownloader_ShouldWriteResponseBodyToFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "release-archive")
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "nested", "archive.tar.gz")
+	if err := NewHTTPDownloader().Download(server.URL+"/archive.tar.gz", "", dest); err != nil {
+		t.Fatalf("Expected download to succeed, got: %v", err)
+	}
+
+	content, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("Expected downloaded file to exist, got: %v", err)
+	}
+	if string(content) != "release-archive" {
+		t.Errorf("Expected downloaded content, got %q", content)
+	}
+}
+
+func TestHTTPDownloader_ShouldFailOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "missing")
+	if err := NewHTTPDownloader().Download(server.URL+"/missing", "", dest); err == nil {
+		t.Errorf("Expected error for 404 response")
+	}
+
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("Expected no file to be written for failed download")
+	}
+}
+
+func TestVerifySHA256_ShouldCompareChecksums(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	helloSHA256 := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if err := VerifySHA256(path, helloSHA256); err != nil {
+		t.Errorf("Expected matching checksum to pass, got: %v", err)
+	}
+
+	if err := VerifySHA256(path, "deadbeef"); err == nil {
+		t.Errorf("Expected mismatching checksum to fail")
+	}
+
+	if err := VerifySHA256(path, ""); err != nil {
+		t.Errorf("Expected empty checksum to be skipped, got: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package download
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Extract unpacks a .tar, .tar.gz, .tgz or .zip archive into dest. When
+// files is not empty, only entries whose path or base name is listed are
+// extracted. Entries escaping dest, symlinks pointing outside of it and
+// entries written through a symlink are rejected.
+func Extract(archive, dest string, files []string) error {
+	name := strings.ToLower(archive)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return extractZip(archive, dest, files)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return extractTar(archive, dest, files, true)
+	case strings.HasSuffix(name, ".tar"):
+		return extractTar(archive, dest, files, false)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", filepath.Base(archive))
+	}
+}
+
+func extractTar(archive, dest string, files []string, compressed bool) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", filepath.Base(archive), err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	found := make(map[string]bool)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", filepath.Base(archive), err)
+		}
+
+		if !wanted(header.Name, files, found) {
+			continue
+		}
+
+		target, err := entryPath(dest, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0o755)
+		case tar.TypeReg:
+			err = writeEntry(target, tr, header.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			err = writeSymlink(dest, target, header.Linkname)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return missingFiles(archive, files, found)
+}
+
+func extractZip(archive, dest string, files []string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(archive), err)
+	}
+	defer reader.Close()
+
+	found := make(map[string]bool)
+	for _, entry := range reader.File {
+		if !wanted(entry.Name, files, found) {
+			continue
+		}
+
+		target, err := entryPath(dest, entry.Name)
+		if err != nil {
+			return err
+		}
+
+		if entry.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		content, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s from %s: %w", entry.Name, filepath.Base(archive), err)
+		}
+		err = writeEntry(target, content, entry.Mode().Perm())
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return missingFiles(archive, files, found)
+}
+
+// wanted reports whether an entry is selected by files and marks the
+// selection as found.
+func wanted(name string, files []string, found map[string]bool) bool {
+	if len(files) == 0 {
+		return true
+	}
+
+	name = strings.TrimPrefix(path.Clean(name), "./")
+	for _, file := range files {
+		if name == file || path.Base(name) == file {
+			found[file] = true
+			return true
+		}
+	}
+	return false
+}
+
+func missingFiles(archive string, files []string, found map[string]bool) error {
+	for _, file := range files {
+		if !found[file] {
+			return fmt.Errorf("%s not found in %s", file, filepath.Base(archive))
+		}
+	}
+	return nil
+}
+
+func entryPath(dest, name string) (string, error) {
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	if !within(dest, target) {
+		return "", fmt.Errorf("archive entry %s escapes the destination", name)
+	}
+
+	// An earlier entry may have placed a symlink where this one's parent
+	// directories go; writing through it could land outside dest.
+	for dir := filepath.Dir(target); within(dest, dir) && dir != filepath.Clean(dest); dir = filepath.Dir(dir) {
+		if info, err := os.Lstat(dir); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("archive entry %s is written through the symlink %s", name, dir)
+		}
+	}
+	return target, nil
+}
+
+// within reports whether path is dest or inside it.
+func within(dest, path string) bool {
+	dest = filepath.Clean(dest)
+	return path == dest || strings.HasPrefix(path, dest+string(filepath.Separator))
+}
+
+func writeEntry(target string, r io.Reader, mode os.FileMode) error {
+	if err := WriteFile(target, r); err != nil {
+		return err
+	}
+	if mode == 0 {
+		mode = 0o644
+	}
+	return os.Chmod(target, mode)
+}
+
+func writeSymlink(dest, target, linkname string) error {
+	if filepath.IsAbs(linkname) || !within(dest, filepath.Join(filepath.Dir(target), linkname)) {
+		return fmt.Errorf("archive symlink %s -> %s points outside the destination", filepath.Base(target), linkname)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	os.Remove(target)
+	return os.Symlink(linkname, target)
+}
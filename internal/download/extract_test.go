@@ -0,0 +1,155 @@
+package download
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTarGz(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test that only the requested files are extracted from a tarball, keeping their mode
+func TestExtract_ShouldExtractSelectedFilesFromTarGz(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "tool.tar.gz")
+	writeTarGz(t, archive, map[string]string{"tool-1.0/tool": "binary", "tool-1.0/README.md": "docs"})
+
+	dest := filepath.Join(dir, "out")
+	if err := Extract(archive, dest, []string{"tool"}); err != nil {
+		t.Fatalf("Expected extraction to succeed, got: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dest, "tool-1.0", "tool"))
+	if err != nil {
+		t.Fatalf("Expected selected file to be extracted: %v", err)
+	}
+	if info.Mode().Perm() != 0o755 {
+		t.Errorf("Expected mode 0755, got %o", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(dest, "tool-1.0", "README.md")); err == nil {
+		t.Errorf("Expected unselected file to be left out")
+	}
+}
+
+// Test that zip archives are extracted and missing selections are reported
+func TestExtract_ShouldExtractZipAndReportMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "tool.zip")
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("tool")
+	w.Write([]byte("binary"))
+	zw.Close()
+	if err := os.WriteFile(archive, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Extract(archive, filepath.Join(dir, "out"), nil); err != nil {
+		t.Fatalf("Expected extraction to succeed, got: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "out", "tool")); string(content) != "binary" {
+		t.Errorf("Expected extracted content, got %q", content)
+	}
+
+	err := Extract(archive, filepath.Join(dir, "out"), []string{"other"})
+	if err == nil || !strings.Contains(err.Error(), "other not found") {
+		t.Errorf("Expected missing file error, got: %v", err)
+	}
+}
+
+// Test that entries pointing outside the destination are rejected
+func TestExtract_ShouldRejectEntriesEscapingDestination(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil.tar.gz")
+	writeTarGz(t, archive, map[string]string{"../escaped": "x"})
+
+	err := Extract(archive, filepath.Join(dir, "out"), nil)
+	if err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Errorf("Expected escaping entry to be rejected, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped")); err == nil {
+		t.Errorf("Expected nothing written outside the destination")
+	}
+}
+
+// Test that symlinks leaving the destination, and entries written through a symlink, are rejected
+func TestExtract_ShouldRejectSymlinkEscapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []*tar.Header
+	}{
+		{"absolute link", []*tar.Header{{Name: "dir", Linkname: "/tmp", Typeflag: tar.TypeSymlink}}},
+		{"relative link", []*tar.Header{{Name: "dir", Linkname: "../..", Typeflag: tar.TypeSymlink}}},
+		{"write through link", []*tar.Header{
+			{Name: "bin", Typeflag: tar.TypeDir, Mode: 0o755},
+			{Name: "dir", Linkname: "bin", Typeflag: tar.TypeSymlink},
+			{Name: "dir/x", Typeflag: tar.TypeReg, Mode: 0o644},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			archive := filepath.Join(dir, "evil.tar")
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			for _, header := range tt.headers {
+				if err := tw.WriteHeader(header); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tw.Close()
+			if err := os.WriteFile(archive, buf.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := Extract(archive, filepath.Join(dir, "out"), nil); err == nil {
+				t.Errorf("Expected archive to be rejected")
+			}
+		})
+	}
+}
+
+// Test that symlinks staying inside the destination are still extracted
+func TestExtract_ShouldKeepInternalSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "tool.tar")
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	tw.WriteHeader(&tar.Header{Name: "bin/tool-1.0", Typeflag: tar.TypeReg, Mode: 0o755, Size: 4})
+	tw.Write([]byte("tool"))
+	tw.WriteHeader(&tar.Header{Name: "bin/tool", Linkname: "tool-1.0", Typeflag: tar.TypeSymlink})
+	tw.Close()
+	if err := os.WriteFile(archive, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "out")
+	if err := Extract(archive, out, nil); err != nil {
+		t.Fatalf("Expected archive with internal symlink to extract, got: %v", err)
+	}
+	if content, err := os.ReadFile(filepath.Join(out, "bin", "tool")); err != nil || string(content) != "tool" {
+		t.Errorf("Expected symlink to resolve inside the destination, got %q: %v", content, err)
+	}
+}
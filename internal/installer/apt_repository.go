@@ -0,0 +1,179 @@
+package installer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/keyring"
+)
+
+const (
+	defaultKeyringDir = "/etc/apt/keyrings"
+	defaultSourcesDir = "/etc/apt/sources.list.d"
+	osReleasePath     = "/etc/os-release"
+	codenameKey       = "VERSION_CODENAME="
+	codenameHolder    = "{codename}"
+
+	repoFileInstallCmd = "sudo install -D -m 0644 %s %s"
+	repoFileRemoveCmd  = "sudo rm -f %s"
+	aptRemoveCmd       = "sudo apt remove -y %s"
+
+	armoredKeyExt = ".asc"
+	binaryKeyExt  = ".gpg"
+	sourceListExt = ".list"
+)
+
+// setupRepository adds the tool's apt repository and signing key. Files that
+// already have the expected content are left untouched, so running it again
+// is a no-op.
+func (a *APTInstaller) setupRepository(repo *config.AptRepository) error {
+	if repo.Name == "" || repo.URL == "" || repo.KeyURL == "" {
+		return fmt.Errorf("apt_repository requires name, url and key_url")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "devenv-apt-")
+	if err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	keyFile := filepath.Join(tmpDir, "key")
+	if err := a.Downloader.Download(repo.KeyURL, "", keyFile); err != nil {
+		return fmt.Errorf("failed to download signing key for %s: %w", repo.Name, err)
+	}
+
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+
+	fingerprints, err := keyring.Fingerprints(key)
+	if err != nil {
+		return fmt.Errorf("invalid signing key from %s: %w", repo.KeyURL, err)
+	}
+
+	if repo.Fingerprint != "" && !slices.Contains(fingerprints, keyring.NormalizeFingerprint(repo.Fingerprint)) {
+		return fmt.Errorf("signing key fingerprint mismatch for %s: expected %s, got %s",
+			repo.Name, keyring.NormalizeFingerprint(repo.Fingerprint), strings.Join(fingerprints, ", "))
+	}
+
+	keyPath := a.keyPath(repo, keyring.IsArmored(key))
+	if err := a.installRepoFile(keyFile, key, keyPath); err != nil {
+		return err
+	}
+
+	suite, err := a.expandSuite(repo.Suite)
+	if err != nil {
+		return err
+	}
+
+	line := []byte(a.sourceLine(repo, keyPath, suite))
+	listFile := filepath.Join(tmpDir, "list")
+	if err := os.WriteFile(listFile, line, 0o644); err != nil {
+		return err
+	}
+
+	return a.installRepoFile(listFile, line, a.listPath(repo))
+}
+
+// removeRepository removes the repository's source list and signing key.
+func (a *APTInstaller) removeRepository(repo *config.AptRepository) error {
+	paths := []string{
+		a.listPath(repo),
+		a.keyPath(repo, true),
+		a.keyPath(repo, false),
+	}
+
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = shellQuote(path)
+	}
+
+	if err := a.CommandExecutor.Execute(fmt.Sprintf(repoFileRemoveCmd, strings.Join(quoted, " "))); err != nil {
+		return fmt.Errorf("failed to remove apt repository %s: %w", repo.Name, err)
+	}
+	return nil
+}
+
+func (a *APTInstaller) installRepoFile(src string, content []byte, dest string) error {
+	if existing, err := os.ReadFile(dest); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+
+	if err := a.CommandExecutor.Execute(fmt.Sprintf(repoFileInstallCmd, shellQuote(src), shellQuote(dest))); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+	return nil
+}
+
+func (a *APTInstaller) sourceLine(repo *config.AptRepository, keyPath, suite string) string {
+	archs := repo.Architectures
+	if len(archs) == 0 {
+		archs = []string{hostArch(a.Arch)}
+	}
+
+	fields := append([]string{"deb", fmt.Sprintf("[arch=%s signed-by=%s]", strings.Join(archs, ","), keyPath), repo.URL, suite}, repo.Components...)
+	return strings.Join(fields, " ") + "\n"
+}
+
+func (a *APTInstaller) expandSuite(suite string) (string, error) {
+	if !strings.Contains(suite, codenameHolder) {
+		return suite, nil
+	}
+
+	codename := a.Codename
+	if codename == nil {
+		codename = distributionCodename
+	}
+
+	name, err := codename()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine distribution codename: %w", err)
+	}
+	return strings.ReplaceAll(suite, codenameHolder, name), nil
+}
+
+func (a *APTInstaller) keyPath(repo *config.AptRepository, armored bool) string {
+	ext := binaryKeyExt
+	if armored {
+		ext = armoredKeyExt
+	}
+	return filepath.Join(dirOrDefault(a.KeyringDir, defaultKeyringDir), repo.Name+ext)
+}
+
+func (a *APTInstaller) listPath(repo *config.AptRepository) string {
+	return filepath.Join(dirOrDefault(a.SourcesDir, defaultSourcesDir), repo.Name+sourceListExt)
+}
+
+func dirOrDefault(dir, fallback string) string {
+	if dir == "" {
+		return fallback
+	}
+	return dir
+}
+
+// distributionCodename reads VERSION_CODENAME from /etc/os-release.
+func distributionCodename() (string, error) {
+	file, err := os.Open(osReleasePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, found := strings.CutPrefix(scanner.Text(), codenameKey); found {
+			return strings.Trim(value, `"'`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s has no VERSION_CODENAME", osReleasePath)
+}
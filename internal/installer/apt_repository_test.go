@@ -0,0 +1,171 @@
+package installer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/config"
+)
+
+// repoTestKey is an ed25519 public key generated with gpg for these tests.
+const repoTestKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mDMEatVOBxYJKwYBBAHaRw8BAQdABpI5U3ZbQT0XFG95fnuri+EAZe42Mo5Ckizh
+LVfswMm0HmRldmVudiB0ZXN0IDx0ZXN0QGV4YW1wbGUuY29tPoiQBBMWCAA4FiEE
+RnusQDy3hXPg71w4hpfIB/Fqw0oFAmrVTgcCGwEFCwkIBwIGFQoJCAsCBBYCAwEC
+HgECF4AACgkQhpfIB/Fqw0rYmAD/X8mlgT3dy+bi89fYlpdw0+CVyRL6Lqz3vGyD
+KUsU4gkBAJTGVFQ4yFDWXOo3E2SJfAK5YTsoXUGeVMQLU9J6LgcL
+=c1Ia
+-----END PGP PUBLIC KEY BLOCK-----`
+
+const repoTestFingerprint = "467B AC40 3CB7 8573 E0EF  5C38 8697 C807 F16A C34A"
+
+func newRepoTestInstaller(t *testing.T, executor CommandExecutor) *APTInstaller {
+	root := t.TempDir()
+	return &APTInstaller{
+		CommandExecutor: executor,
+		Downloader:      &stubDownloader{content: []byte(repoTestKey)},
+		Arch:            "amd64",
+		KeyringDir:      filepath.Join(root, "keyrings"),
+		SourcesDir:      filepath.Join(root, "sources.list.d"),
+		Codename:        func() (string, error) { return "noble", nil },
+	}
+}
+
+func dockerRepoTool() config.ToolConfig {
+	return config.ToolConfig{
+		DisplayName:   "Docker Engine",
+		BinaryName:    "docker",
+		InstallMethod: "apt",
+		PackageName:   "docker-ce",
+		AptRepository: &config.AptRepository{
+			Name:        "docker",
+			URL:         "https://download.docker.com/linux/ubuntu",
+			KeyURL:      "https://download.docker.com/linux/ubuntu/gpg",
+			Fingerprint: repoTestFingerprint,
+			Suite:       "{codename}",
+			Components:  []string{"stable"},
+		},
+	}
+}
+
+// fileInstallingExecutor performs "sudo install -D" commands so repeated
+// setups see the files written by earlier ones.
+type fileInstallingExecutor struct {
+	MockCommandExecutor
+}
+
+func (f *fileInstallingExecutor) Execute(command string) error {
+	f.ExecutedCommands = append(f.ExecutedCommands, command)
+	fields := strings.Fields(command)
+	if len(fields) == 7 && fields[1] == "install" && fields[2] == "-D" {
+		content, err := os.ReadFile(strings.Trim(fields[5], "'"))
+		if err != nil {
+			return err
+		}
+		dest := strings.Trim(fields[6], "'")
+		os.MkdirAll(filepath.Dir(dest), 0o755)
+		return os.WriteFile(dest, content, 0o644)
+	}
+	return nil
+}
+
+func TestAPTInstaller_ShouldSetUpRepositoryBeforeInstalling(t *testing.T) {
+	// Test that the keyring and source list are written before apt update and install
+	executor := &fileInstallingExecutor{}
+	installer := newRepoTestInstaller(t, executor)
+
+	if err := installer.Install(dockerRepoTool()); err != nil {
+		t.Fatalf("Expected install to succeed, got: %v", err)
+	}
+
+	commands := executor.ExecutedCommands
+	if len(commands) != 4 {
+		t.Fatalf("Expected key, source list, update and install commands, got %v", commands)
+	}
+	if !strings.HasSuffix(commands[0], "keyrings/docker.asc") || !strings.HasSuffix(commands[1], "sources.list.d/docker.list") {
+		t.Errorf("Expected keyring and source list to be installed first, got %v", commands[:2])
+	}
+	if commands[2] != "sudo apt update" || commands[3] != "sudo apt install -y docker-ce" {
+		t.Errorf("Expected apt update and install after repository setup, got %v", commands[2:])
+	}
+
+	list, _ := os.ReadFile(filepath.Join(installer.SourcesDir, "docker.list"))
+	expected := "deb [arch=amd64 signed-by=" + filepath.Join(installer.KeyringDir, "docker.asc") + "] https://download.docker.com/linux/ubuntu noble stable\n"
+	if string(list) != expected {
+		t.Errorf("Expected source line %q, got %q", expected, list)
+	}
+}
+
+func TestAPTInstaller_ShouldSetUpRepositoryIdempotently(t *testing.T) {
+	executor := &fileInstallingExecutor{}
+	installer := newRepoTestInstaller(t, executor)
+	tool := dockerRepoTool()
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatal(err)
+	}
+	executor.ExecutedCommands = nil
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected second install to succeed, got: %v", err)
+	}
+
+	for _, command := range executor.ExecutedCommands {
+		if strings.Contains(command, "install -D") {
+			t.Errorf("Expected unchanged repository files not to be rewritten, got: %s", command)
+		}
+	}
+}
+
+func TestAPTInstaller_ShouldRejectKeyWithWrongFingerprint(t *testing.T) {
+	executor := &MockCommandExecutor{}
+	installer := newRepoTestInstaller(t, executor)
+	tool := dockerRepoTool()
+	tool.AptRepository.Fingerprint = "9DC8 5822 9FC7 DD38 854A  E2D8 8D81 803C 0EBF CD88"
+
+	err := installer.Install(tool)
+	if err == nil || !strings.Contains(err.Error(), "fingerprint mismatch") {
+		t.Fatalf("Expected fingerprint mismatch, got: %v", err)
+	}
+
+	if len(executor.ExecutedCommands) != 0 {
+		t.Errorf("Expected nothing to be written for an untrusted key, got %v", executor.ExecutedCommands)
+	}
+}
+
+func TestAPTInstaller_ShouldRemoveRepositoryOnUninstall(t *testing.T) {
+	executor := &MockCommandExecutor{}
+	installer := newRepoTestInstaller(t, executor)
+
+	if err := installer.Uninstall(dockerRepoTool()); err != nil {
+		t.Fatalf("Expected uninstall to succeed, got: %v", err)
+	}
+
+	commands := executor.ExecutedCommands
+	if len(commands) != 2 || commands[0] != "sudo apt remove -y docker-ce" {
+		t.Fatalf("Expected package removal then repository removal, got %v", commands)
+	}
+	if !strings.HasPrefix(commands[1], "sudo rm -f ") || !strings.Contains(commands[1], "docker.list") || !strings.Contains(commands[1], "docker.asc") {
+		t.Errorf("Expected source list and keyring to be removed, got: %s", commands[1])
+	}
+}
+
+func TestOrchestrator_ShouldRefuseToUninstallLegacyScriptTools(t *testing.T) {
+	mockExecutor := &MockCommandExecutor{}
+	orchestrator := &InstallationOrchestrator{ScriptInstaller: &ScriptInstaller{CommandExecutor: mockExecutor}}
+	tools := map[string]config.ToolConfig{
+		"neovim": {DisplayName: "Neovim", InstallMethod: "script", InstallScript: "../../install_scripts/neovim.sh"},
+	}
+
+	result := orchestrator.Uninstall([]string{"neovim"}, tools)["neovim"]
+	if !errors.Is(result.Error, ErrUninstallUnsupported) {
+		t.Errorf("Expected ErrUninstallUnsupported, got: %v", result.Error)
+	}
+	if len(mockExecutor.ExecutedCommands) != 0 {
+		t.Errorf("Expected no commands for a script without an uninstall verb, got %v", mockExecutor.ExecutedCommands)
+	}
+}
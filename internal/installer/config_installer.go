@@ -0,0 +1,86 @@
+package installer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/download"
+)
+
+const configBackupMsg = "Backed up existing %s to %s\n"
+
+// ConfigInstaller writes a tool's config template to its config path after
+// the tool is installed. Existing files are backed up through the journal
+// before being replaced.
+type ConfigInstaller struct {
+	Assets  AssetResolver
+	Journal ChangeRecorder
+	// HomeDir expands ~ in config paths; defaults to the user's home.
+	HomeDir string
+	// Notify reports backups; they are printed when unset.
+	Notify func(format string, args ...any)
+}
+
+// Apply writes tool's config template. Tools without a template or config
+// path are left alone, as are configs that already match the template.
+func (c *ConfigInstaller) Apply(tool config.ToolConfig) error {
+	if tool.ConfigTemplate == "" || tool.ConfigPath == "" {
+		return nil
+	}
+
+	templatePath := tool.ConfigTemplate
+	if c.Assets != nil {
+		resolved, err := c.Assets.Resolve(tool.ConfigTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to locate config template %s: %w", tool.ConfigTemplate, err)
+		}
+		templatePath = resolved
+	}
+
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return fmt.Errorf("failed to read config template %s: %w", tool.ConfigTemplate, err)
+	}
+
+	target, err := expandHome(tool.ConfigPath, c.HomeDir)
+	if err != nil {
+		return err
+	}
+
+	if existing, err := os.ReadFile(target); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+
+	change, err := recordFileChange(c.Journal, tool.BinaryName, target, false)
+	if err != nil {
+		return err
+	}
+	if change.Backup != "" {
+		notice(c.Notify, configBackupMsg, target, change.Backup)
+	}
+
+	if err := download.WriteFile(target, bytes.NewReader(content)); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", target, err)
+	}
+	return os.Chmod(target, 0o644)
+}
+
+// expandHome replaces a leading ~ in path with home, or the user's home
+// directory when home is empty.
+func expandHome(path, home string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	if home == "" {
+		var err error
+		if home, err = os.UserHomeDir(); err != nil {
+			return "", fmt.Errorf("failed to determine home directory: %w", err)
+		}
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
@@ -0,0 +1,99 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/state"
+)
+
+func writeTemplate(t *testing.T, content string) string {
+	t.Helper()
+	template := filepath.Join(t.TempDir(), "tmux.conf")
+	if err := os.WriteFile(template, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return template
+}
+
+func TestConfigInstaller_ShouldWriteTemplateAndJournalIt(t *testing.T) {
+	home := t.TempDir()
+	journal, err := state.StartJournal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installer := &ConfigInstaller{Journal: journal, HomeDir: home}
+	tool := config.ToolConfig{
+		BinaryName:     "tmux",
+		ConfigPath:     "~/.tmux.conf",
+		ConfigTemplate: writeTemplate(t, "set -g prefix C-a\n"),
+	}
+
+	if err := installer.Apply(tool); err != nil {
+		t.Fatalf("Expected config to be applied, got: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(home, ".tmux.conf"))
+	if string(content) != "set -g prefix C-a\n" {
+		t.Errorf("Expected template content in config, got %q", content)
+	}
+
+	if len(journal.Changes) != 1 || journal.Changes[0].Kind != state.ChangeCreated {
+		t.Errorf("Expected one created change in journal, got %+v", journal.Changes)
+	}
+}
+
+func TestConfigInstaller_ShouldBackUpExistingConfig(t *testing.T) {
+	home := t.TempDir()
+	target := filepath.Join(home, ".tmux.conf")
+	os.WriteFile(target, []byte("my settings\n"), 0o600)
+
+	journal, err := state.StartJournal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installer := &ConfigInstaller{Journal: journal, HomeDir: home}
+	tool := config.ToolConfig{
+		BinaryName:     "tmux",
+		ConfigPath:     "~/.tmux.conf",
+		ConfigTemplate: writeTemplate(t, "set -g prefix C-a\n"),
+	}
+
+	if err := installer.Apply(tool); err != nil {
+		t.Fatalf("Expected config to be applied, got: %v", err)
+	}
+
+	change := journal.Changes[0]
+	if change.Kind != state.ChangeReplaced || change.Mode.Perm() != 0o600 {
+		t.Fatalf("Expected replaced change with original mode, got %+v", change)
+	}
+
+	backup, _ := os.ReadFile(change.Backup)
+	if string(backup) != "my settings\n" {
+		t.Errorf("Expected backup of previous config, got %q", backup)
+	}
+}
+
+func TestConfigInstaller_ShouldSkipUnchangedConfig(t *testing.T) {
+	home := t.TempDir()
+	os.WriteFile(filepath.Join(home, ".tmux.conf"), []byte("same\n"), 0o644)
+
+	journal, err := state.StartJournal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installer := &ConfigInstaller{Journal: journal, HomeDir: home}
+	tool := config.ToolConfig{ConfigPath: "~/.tmux.conf", ConfigTemplate: writeTemplate(t, "same\n")}
+
+	if err := installer.Apply(tool); err != nil {
+		t.Fatalf("Expected unchanged config to succeed, got: %v", err)
+	}
+	if len(journal.Changes) != 0 {
+		t.Errorf("Expected nothing journaled for unchanged config, got %+v", journal.Changes)
+	}
+}
@@ -0,0 +1,253 @@
+package installer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/download"
+	"github.com/petersenjoern/devenv/internal/state"
+)
+
+type stubDownloader struct {
+	content []byte
+	urls    []string
+	err     error
+}
+
+func (s *stubDownloader) Download(url, expectedSHA256, dest string) error {
+	s.urls = append(s.urls, url)
+	if s.err != nil {
+		return s.err
+	}
+	if err := os.WriteFile(dest, s.content, 0o644); err != nil {
+		return err
+	}
+	return download.VerifySHA256(dest, expectedSHA256)
+}
+
+func TestDownloadInstaller_ShouldDownloadAndInstallBinary(t *testing.T) {
+	// Test that DownloadInstaller fetches the URL and installs it to the install location
+	mockExecutor := &MockCommandExecutor{}
+	downloader := &stubDownloader{content: []byte("binary")}
+	installer := &DownloadInstaller{CommandExecutor: mockExecutor, Downloader: downloader}
+
+	tool := config.ToolConfig{
+		DisplayName:     "Broot Tree Explorer",
+		BinaryName:      "broot",
+		InstallMethod:   "download",
+		DownloadURL:     "https://example.com/broot",
+		InstallLocation: "/usr/local/bin/broot",
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected download installation to succeed, got: %v", err)
+	}
+
+	if len(downloader.urls) != 1 || downloader.urls[0] != tool.DownloadURL {
+		t.Errorf("Expected download of %s, got %v", tool.DownloadURL, downloader.urls)
+	}
+
+	if len(mockExecutor.ExecutedCommands) != 1 {
+		t.Fatalf("Expected 1 install command, got %v", mockExecutor.ExecutedCommands)
+	}
+
+	command := mockExecutor.ExecutedCommands[0]
+	if !strings.HasPrefix(command, "sudo install -m 0755 ") || !strings.HasSuffix(command, " /usr/local/bin/broot") {
+		t.Errorf("Expected install command targeting /usr/local/bin/broot, got: %s", command)
+	}
+}
+
+func TestDownloadInstaller_ShouldRejectChecksumMismatch(t *testing.T) {
+	// Test that a download not matching the configured checksum is never installed
+	mockExecutor := &MockCommandExecutor{}
+	installer := &DownloadInstaller{
+		CommandExecutor: mockExecutor,
+		Downloader:      &stubDownloader{content: []byte("tampered")},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Broot Tree Explorer",
+		BinaryName:    "broot",
+		InstallMethod: "download",
+		DownloadURL:   "https://example.com/broot",
+		SHA256:        strings.Repeat("0", 64),
+	}
+
+	err := installer.Install(tool)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected checksum mismatch error, got: %v", err)
+	}
+
+	if len(mockExecutor.ExecutedCommands) != 0 {
+		t.Errorf("Expected no install command after failed verification, got %v", mockExecutor.ExecutedCommands)
+	}
+}
+
+func TestDownloadInstaller_ShouldHandleDownloadFailure(t *testing.T) {
+	// Test that download errors are reported
+	installer := &DownloadInstaller{
+		CommandExecutor: &MockCommandExecutor{},
+		Downloader:      &stubDownloader{err: errors.New("connection refused")},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Broot Tree Explorer",
+		BinaryName:    "broot",
+		InstallMethod: "download",
+		DownloadURL:   "https://example.com/broot",
+	}
+
+	if err := installer.Install(tool); err == nil {
+		t.Errorf("Expected error when download fails")
+	}
+}
+
+type stubPackageSource map[string]string
+
+func (s stubPackageSource) DebPaths(packageName string) ([]string, bool) {
+	path, found := s[packageName]
+	return []string{path}, found
+}
+
+func TestAPTInstaller_ShouldInstallFromLocalPackagesWithoutUpdate(t *testing.T) {
+	// Test that bundle installs use local .deb files and never contact apt mirrors
+	mockExecutor := &MockCommandExecutor{}
+	installer := &APTInstaller{
+		CommandExecutor: mockExecutor,
+		Packages:        stubPackageSource{"git": "/bundle/debs/git_1.0_amd64.deb"},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Git Version Control",
+		BinaryName:    "git",
+		InstallMethod: "apt",
+		PackageName:   "git",
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected local package installation to succeed, got: %v", err)
+	}
+
+	expected := []string{"sudo apt install -y /bundle/debs/git_1.0_amd64.deb"}
+	if len(mockExecutor.ExecutedCommands) != 1 || mockExecutor.ExecutedCommands[0] != expected[0] {
+		t.Errorf("Expected commands %v, got %v", expected, mockExecutor.ExecutedCommands)
+	}
+}
+
+func TestAPTInstaller_ShouldFailWhenPackageIsMissingFromLocalSource(t *testing.T) {
+	mockExecutor := &MockCommandExecutor{}
+	installer := &APTInstaller{
+		CommandExecutor: mockExecutor,
+		Packages:        stubPackageSource{},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Vim Editor",
+		BinaryName:    "vim",
+		InstallMethod: "apt",
+		PackageName:   "vim",
+	}
+
+	if err := installer.Install(tool); err == nil {
+		t.Errorf("Expected error when package is not in local source")
+	}
+
+	if len(mockExecutor.ExecutedCommands) != 0 {
+		t.Errorf("Expected no commands, got %v", mockExecutor.ExecutedCommands)
+	}
+}
+
+func TestDownloadInstaller_ShouldSelectURLForArchitecture(t *testing.T) {
+	// Test that per-architecture URLs are resolved with the installer's architecture
+	downloader := &stubDownloader{content: []byte("binary")}
+	installer := &DownloadInstaller{
+		CommandExecutor: &MockCommandExecutor{},
+		Downloader:      downloader,
+		Arch:            "arm64",
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Broot Tree Explorer",
+		BinaryName:    "broot",
+		InstallMethod: "download",
+		DownloadURLs: map[string]string{
+			"amd64": "https://example.com/x86_64-unknown-linux-gnu/broot",
+			"arm64": "https://example.com/aarch64-unknown-linux-musl/broot",
+		},
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected installation to succeed, got: %v", err)
+	}
+
+	if len(downloader.urls) != 1 || downloader.urls[0] != tool.DownloadURLs["arm64"] {
+		t.Errorf("Expected arm64 URL to be downloaded, got %v", downloader.urls)
+	}
+}
+
+func TestDownloadInstaller_ShouldInstallIntoUserPrefixWithoutSudo(t *testing.T) {
+	// Test that --user mode places binaries in ~/.local/bin without sudo
+	mockExecutor := &MockCommandExecutor{}
+	installer := &DownloadInstaller{
+		CommandExecutor: mockExecutor,
+		Downloader:      &stubDownloader{content: []byte("binary")},
+		UserPrefix:      "/home/dev/.local",
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:     "Broot Tree Explorer",
+		BinaryName:      "broot",
+		InstallMethod:   "download",
+		DownloadURL:     "https://example.com/broot",
+		InstallLocation: "/usr/local/bin/broot",
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected user install to succeed, got: %v", err)
+	}
+
+	command := mockExecutor.ExecutedCommands[0]
+	if strings.Contains(command, "sudo") {
+		t.Errorf("Expected no sudo in user mode, got: %s", command)
+	}
+	if !strings.HasSuffix(command, " /home/dev/.local/bin/broot") {
+		t.Errorf("Expected install into ~/.local/bin, got: %s", command)
+	}
+}
+
+func TestDownloadInstaller_ShouldJournalPlacedBinary(t *testing.T) {
+	// Test that system-wide binaries are journaled as privileged changes for rollback
+	journal, err := state.StartJournal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installer := &DownloadInstaller{
+		CommandExecutor: &MockCommandExecutor{},
+		Downloader:      &stubDownloader{content: []byte("binary")},
+		Journal:         journal,
+	}
+
+	tool := config.ToolConfig{
+		BinaryName:      "broot",
+		InstallMethod:   "download",
+		DownloadURL:     "https://example.com/broot",
+		InstallLocation: filepath.Join(t.TempDir(), "broot"),
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected install to succeed, got: %v", err)
+	}
+
+	if len(journal.Changes) != 1 {
+		t.Fatalf("Expected one journaled change, got %+v", journal.Changes)
+	}
+	change := journal.Changes[0]
+	if change.Path != tool.InstallLocation || change.Kind != state.ChangeCreated || !change.Privileged {
+		t.Errorf("Expected privileged created change for %s, got %+v", tool.InstallLocation, change)
+	}
+}
@@ -0,0 +1,160 @@
+package installer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventType identifies what happened during an installation run.
+type EventType string
+
+const (
+	EventPlanResolved   EventType = "plan-resolved"
+	EventToolStarted    EventType = "tool-started"
+	EventCommandStarted EventType = "command-started"
+	EventOutputLine     EventType = "output-line"
+	EventToolFinished   EventType = "tool-finished"
+	EventRunFinished    EventType = "run-finished"
+	// EventMessage carries notices such as retries and method fallbacks.
+	EventMessage EventType = "message"
+)
+
+// Event is a single step of an installation run. Only the fields relevant
+// to its type are set.
+type Event struct {
+	Type        EventType     `json:"type"`
+	Time        time.Time     `json:"time"`
+	Tool        string        `json:"tool,omitempty"`
+	DisplayName string        `json:"display_name,omitempty"`
+	Plan        []string      `json:"plan,omitempty"`
+	Command     string        `json:"command,omitempty"`
+	Line        string        `json:"line,omitempty"`
+	Status      InstallStatus `json:"status,omitempty"`
+	Method      string        `json:"method,omitempty"`
+	Version     string        `json:"version,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	Duration    float64       `json:"duration_seconds,omitempty"`
+	// Summary counts the tools of a finished run per status.
+	Summary map[InstallStatus]int `json:"summary,omitempty"`
+	// Results holds the outcome of every tool of a finished run for
+	// in-process sinks.
+	Results map[string]InstallationResult `json:"-"`
+}
+
+// EventSink receives the events of an installation run.
+type EventSink interface {
+	Emit(event Event)
+}
+
+// EventSinks passes every event to each sink in turn.
+type EventSinks []EventSink
+
+func (s EventSinks) Emit(event Event) {
+	for _, sink := range s {
+		sink.Emit(event)
+	}
+}
+
+// JSONLSink writes each event as a line of JSON.
+type JSONLSink struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{encoder: json.NewEncoder(w)}
+}
+
+func (s *JSONLSink) Emit(event Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoder.Encode(event)
+}
+
+// EventEmitter stamps events with the time and the tool being installed
+// before passing them to Sink. Command output written to it is emitted line
+// by line. A nil emitter discards everything.
+type EventEmitter struct {
+	Sink EventSink
+
+	mu      sync.Mutex
+	tool    string
+	partial []byte
+}
+
+func (e *EventEmitter) Emit(event Event) {
+	if e == nil || e.Sink == nil {
+		return
+	}
+
+	e.mu.Lock()
+	if event.Tool == "" {
+		event.Tool = e.tool
+	}
+	e.mu.Unlock()
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	e.Sink.Emit(event)
+}
+
+// CommandStarted emits a command-started event for the current tool.
+func (e *EventEmitter) CommandStarted(command string) {
+	e.Emit(Event{Type: EventCommandStarted, Command: command})
+}
+
+// Write emits an output-line event for every complete line in p.
+func (e *EventEmitter) Write(p []byte) (int, error) {
+	if e == nil {
+		return len(p), nil
+	}
+
+	e.mu.Lock()
+	e.partial = append(e.partial, p...)
+	var lines []string
+	for {
+		end := bytes.IndexByte(e.partial, '\n')
+		if end < 0 {
+			break
+		}
+		lines = append(lines, string(bytes.TrimRight(e.partial[:end], "\r")))
+		e.partial = e.partial[end+1:]
+	}
+	e.mu.Unlock()
+
+	for _, line := range lines {
+		e.Emit(Event{Type: EventOutputLine, Line: line})
+	}
+	return len(p), nil
+}
+
+// flush emits output left without a trailing newline.
+func (e *EventEmitter) flush() {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	partial := string(e.partial)
+	e.partial = nil
+	e.mu.Unlock()
+
+	if partial != "" {
+		e.Emit(Event{Type: EventOutputLine, Line: partial})
+	}
+}
+
+// setTool attributes later events to toolName.
+func (e *EventEmitter) setTool(toolName string) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tool = toolName
+}
@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/tui"
+)
+
+type recordingSink struct {
+	events []Event
+}
+
+func (r *recordingSink) Emit(event Event) {
+	r.events = append(r.events, event)
+}
+
+// Test that a run emits plan, tool, command and output events attributed to the tool being installed
+func TestOrchestrator_ShouldEmitRunEvents(t *testing.T) {
+	sink := &recordingSink{}
+	events := &EventEmitter{Sink: sink}
+	executor := &RealCommandExecutor{Capture: events, Started: events.CommandStarted}
+	orchestrator := &InstallationOrchestrator{
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: executor},
+		Events:          events,
+	}
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{{Category: "development", Tools: []string{"tool"}}},
+	}
+	tools := map[string]config.ToolConfig{
+		"tool": {DisplayName: "Tool", InstallMethod: "script", InstallScript: writeScript(t, "echo first\nprintf second")},
+	}
+
+	orchestrator.ExecuteInstallations(selections, tools)
+
+	var types []string
+	for _, event := range sink.events {
+		types = append(types, string(event.Type))
+		if event.Time.IsZero() {
+			t.Errorf("Expected %s event to be timestamped", event.Type)
+		}
+	}
+	expected := "plan-resolved tool-started command-started output-line output-line tool-finished run-finished"
+	if strings.Join(types, " ") != expected {
+		t.Fatalf("Expected events %q, got %q", expected, strings.Join(types, " "))
+	}
+
+	if line := sink.events[3]; line.Tool != "tool" || line.Line != "first" {
+		t.Errorf("Expected first output line of tool, got %+v", line)
+	}
+	if line := sink.events[4]; line.Line != "second" {
+		t.Errorf("Expected unterminated output to be emitted before the tool finishes, got %+v", line)
+	}
+	if finished := sink.events[5]; finished.Status != StatusInstalled || finished.Method != "script" {
+		t.Errorf("Expected tool-finished with status and method, got %+v", finished)
+	}
+	if last := sink.events[6]; last.Summary[StatusInstalled] != 1 || len(last.Results) != 1 {
+		t.Errorf("Expected run-finished with summary and results, got %+v", last)
+	}
+}
+
+// Test that the JSON lines sink writes one decodable event per line
+func TestJSONLSink_ShouldWriteOneEventPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONLSink(&buf)
+
+	sink.Emit(Event{Type: EventToolStarted, Tool: "git"})
+	sink.Emit(Event{Type: EventToolFinished, Tool: "git", Status: StatusFailed, Error: "boom"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+	if event.Type != EventToolFinished || event.Status != StatusFailed || event.Error != "boom" {
+		t.Errorf("Expected decoded tool-finished event, got %+v", event)
+	}
+}
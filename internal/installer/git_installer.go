@@ -0,0 +1,126 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/petersenjoern/devenv/internal/config"
+)
+
+const (
+	gitCloneCmd     = "git clone --quiet %s %s"
+	gitSetOriginCmd = "git -C %s remote set-url origin %s"
+	gitFetchRefCmd  = "git -C %s fetch --quiet --tags %s %s"
+	gitCheckoutCmd  = "git -C %s checkout --quiet --detach FETCH_HEAD"
+	gitPostCloneCmd = "cd %s && %ssh -c %s"
+	gitRemoveCmd    = "rm -rf %s"
+
+	gitDefaultRef = "HEAD"
+	gitOrigin     = "origin"
+)
+
+// RepoSource provides local copies of git repositories, e.g. the git
+// bundles of an offline bundle.
+type RepoSource interface {
+	RepoPath(repo string) (string, bool)
+}
+
+// GitInstaller installs tools by cloning a repository. Running it again on
+// an existing clone fetches and checks out the configured ref instead.
+type GitInstaller struct {
+	CommandExecutor CommandExecutor
+	// Repos, when set, clones and fetches from local copies of the
+	// repositories it has instead of the network.
+	Repos RepoSource
+	// Env is exported to the post-clone command.
+	Env map[string]string
+	// HomeDir expands ~ in destinations; defaults to the user's home.
+	HomeDir string
+}
+
+func NewGitInstaller() *GitInstaller {
+	return &GitInstaller{
+		CommandExecutor: &RealCommandExecutor{},
+	}
+}
+
+func (g *GitInstaller) Install(tool config.ToolConfig) error {
+	source := tool.Git
+	if source == nil || source.Repo == "" || source.Dest == "" {
+		return fmt.Errorf("git repo and dest are required for git installation method")
+	}
+
+	dest, err := expandHome(source.Dest, g.HomeDir)
+	if err != nil {
+		return err
+	}
+
+	// A local copy is cloned from and then replaced by the real repository
+	// as origin, so later updates go to the network again.
+	from, local := gitOrigin, ""
+	if g.Repos != nil {
+		if path, found := g.Repos.RepoPath(source.Repo); found {
+			from, local = shellQuote(path), path
+		}
+	}
+
+	cloned := false
+	if _, err := os.Stat(filepath.Join(dest, ".git")); err != nil {
+		cloneFrom := source.Repo
+		if local != "" {
+			cloneFrom = local
+		}
+		if err := g.CommandExecutor.Execute(fmt.Sprintf(gitCloneCmd, shellQuote(cloneFrom), shellQuote(dest))); err != nil {
+			return fmt.Errorf("failed to clone %s: %w", source.Repo, err)
+		}
+		if local != "" {
+			if err := g.CommandExecutor.Execute(fmt.Sprintf(gitSetOriginCmd, shellQuote(dest), shellQuote(source.Repo))); err != nil {
+				return fmt.Errorf("failed to set origin of %s: %w", dest, err)
+			}
+		}
+		cloned = true
+	}
+
+	// A fresh clone is already on the default branch; everything else is
+	// brought to the ref's current commit.
+	if !cloned || source.Ref != "" {
+		ref := source.Ref
+		if ref == "" {
+			ref = gitDefaultRef
+		}
+
+		if err := g.CommandExecutor.Execute(fmt.Sprintf(gitFetchRefCmd, shellQuote(dest), from, shellQuote(ref))); err != nil {
+			return fmt.Errorf("failed to fetch %s from %s: %w", ref, source.Repo, err)
+		}
+		if err := g.CommandExecutor.Execute(fmt.Sprintf(gitCheckoutCmd, shellQuote(dest))); err != nil {
+			return fmt.Errorf("failed to check out %s: %w", ref, err)
+		}
+	}
+
+	if source.PostClone != "" {
+		postCloneCmd := fmt.Sprintf(gitPostCloneCmd, shellQuote(dest), envPrefix(g.Env), shellQuote(source.PostClone))
+		if err := g.CommandExecutor.Execute(postCloneCmd); err != nil {
+			return fmt.Errorf("post-clone command for %s failed: %w", tool.DisplayName, err)
+		}
+	}
+
+	return nil
+}
+
+// Uninstall removes the cloned repository.
+func (g *GitInstaller) Uninstall(tool config.ToolConfig) error {
+	if tool.Git == nil || tool.Git.Dest == "" {
+		return fmt.Errorf("git dest is required to uninstall %s", tool.DisplayName)
+	}
+
+	dest, err := expandHome(tool.Git.Dest, g.HomeDir)
+	if err != nil {
+		return err
+	}
+
+	if err := g.CommandExecutor.Execute(fmt.Sprintf(gitRemoveCmd, shellQuote(dest))); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", dest, err)
+	}
+	return nil
+}
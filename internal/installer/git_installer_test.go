@@ -0,0 +1,207 @@
+package installer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/config"
+)
+
+// gitTestRepo is a bare repository fed from a separate working copy, so
+// tests can publish new commits and tags after the tool was cloned.
+type gitTestRepo struct {
+	t    *testing.T
+	bare string
+	work string
+}
+
+func newGitTestRepo(t *testing.T) *gitTestRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "devenv")
+	t.Setenv("GIT_AUTHOR_EMAIL", "devenv@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "devenv")
+	t.Setenv("GIT_COMMITTER_EMAIL", "devenv@example.com")
+	t.Setenv("GIT_CONFIG_GLOBAL", "/dev/null")
+
+	root := t.TempDir()
+	repo := &gitTestRepo{t: t, bare: filepath.Join(root, "tool.git"), work: filepath.Join(root, "work")}
+	repo.git("", "init", "--quiet", "--bare", "--initial-branch=main", repo.bare)
+	repo.git("", "clone", "--quiet", repo.bare, repo.work)
+	return repo
+}
+
+func (r *gitTestRepo) git(dir string, args ...string) {
+	r.t.Helper()
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// commit publishes a commit writing content to version.txt.
+func (r *gitTestRepo) commit(content string) {
+	r.t.Helper()
+	if err := os.WriteFile(filepath.Join(r.work, "version.txt"), []byte(content), 0o644); err != nil {
+		r.t.Fatal(err)
+	}
+	r.git(r.work, "add", "version.txt")
+	r.git(r.work, "commit", "--quiet", "-m", content)
+	r.git(r.work, "push", "--quiet", "origin", "HEAD:main")
+}
+
+func (r *gitTestRepo) tag(name string) {
+	r.t.Helper()
+	r.git(r.work, "tag", name)
+	r.git(r.work, "push", "--quiet", "origin", name)
+}
+
+func gitTool(repo, dest, ref, postClone string) config.ToolConfig {
+	return config.ToolConfig{
+		DisplayName:   "Tool",
+		BinaryName:    "tool",
+		InstallMethod: "git",
+		Git:           &config.GitSource{Repo: repo, Ref: ref, Dest: dest, PostClone: postClone},
+	}
+}
+
+func readVersion(t *testing.T, dest string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dest, "version.txt"))
+	if err != nil {
+		t.Fatalf("Expected checkout to contain version.txt: %v", err)
+	}
+	return string(content)
+}
+
+// Test that a missing destination is cloned and the post-clone command runs inside it
+func TestGitInstaller_ShouldCloneAndRunPostClone(t *testing.T) {
+	repo := newGitTestRepo(t)
+	repo.commit("v1")
+
+	dest := filepath.Join(t.TempDir(), "tool")
+	installer := &GitInstaller{CommandExecutor: &RealCommandExecutor{}, Env: map[string]string{"DEVENV_ARCH": "amd64"}}
+
+	err := installer.Install(gitTool(repo.bare, dest, "", `echo "$DEVENV_ARCH" > installed`))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := readVersion(t, dest); got != "v1" {
+		t.Errorf("Expected v1 checked out, got %q", got)
+	}
+	marker, err := os.ReadFile(filepath.Join(dest, "installed"))
+	if err != nil {
+		t.Fatalf("Expected post-clone command to run in the clone: %v", err)
+	}
+	if strings.TrimSpace(string(marker)) != "amd64" {
+		t.Errorf("Expected post-clone command to see DEVENV_ARCH, got %q", marker)
+	}
+}
+
+// Test that installing over an existing clone fetches and checks out new commits
+func TestGitInstaller_ShouldUpdateExistingClone(t *testing.T) {
+	repo := newGitTestRepo(t)
+	repo.commit("v1")
+
+	dest := filepath.Join(t.TempDir(), "tool")
+	installer := &GitInstaller{CommandExecutor: &RealCommandExecutor{}}
+	tool := gitTool(repo.bare, dest, "", "")
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected first install to succeed, got: %v", err)
+	}
+
+	repo.commit("v2")
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected re-install to succeed, got: %v", err)
+	}
+	if got := readVersion(t, dest); got != "v2" {
+		t.Errorf("Expected existing clone to be updated to v2, got %q", got)
+	}
+}
+
+// Test that a configured tag is checked out, both on clone and on later runs
+func TestGitInstaller_ShouldCheckOutConfiguredRef(t *testing.T) {
+	repo := newGitTestRepo(t)
+	repo.commit("v1")
+	repo.tag("v1.0.0")
+	repo.commit("v2")
+	repo.tag("v2.0.0")
+
+	dest := filepath.Join(t.TempDir(), "tool")
+	installer := &GitInstaller{CommandExecutor: &RealCommandExecutor{}}
+
+	if err := installer.Install(gitTool(repo.bare, dest, "v1.0.0", "")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := readVersion(t, dest); got != "v1" {
+		t.Errorf("Expected tag v1.0.0 checked out, got %q", got)
+	}
+
+	if err := installer.Install(gitTool(repo.bare, dest, "v2.0.0", "")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := readVersion(t, dest); got != "v2" {
+		t.Errorf("Expected tag v2.0.0 checked out, got %q", got)
+	}
+}
+
+// stubRepoSource serves git bundle files by repository URL.
+type stubRepoSource map[string]string
+
+func (s stubRepoSource) RepoPath(repo string) (string, bool) {
+	path, found := s[repo]
+	return path, found
+}
+
+// Test that a bundled repository is cloned and checked out without the network
+func TestGitInstaller_ShouldCloneFromBundledRepository(t *testing.T) {
+	repo := newGitTestRepo(t)
+	repo.commit("v1")
+	repo.tag("v1.0.0")
+	repo.commit("v2")
+
+	gitBundle := filepath.Join(t.TempDir(), "tool.bundle")
+	repo.git(repo.bare, "bundle", "create", "--quiet", gitBundle, "--all")
+
+	remote := "https://example.invalid/tool.git"
+	dest := filepath.Join(t.TempDir(), "tool")
+	installer := &GitInstaller{CommandExecutor: &RealCommandExecutor{}, Repos: stubRepoSource{remote: gitBundle}}
+
+	if err := installer.Install(gitTool(remote, dest, "v1.0.0", "")); err != nil {
+		t.Fatalf("Expected install from the bundled repository, got: %v", err)
+	}
+	if got := readVersion(t, dest); got != "v1" {
+		t.Errorf("Expected v1.0.0 checked out, got %q", got)
+	}
+
+	origin, err := exec.Command("git", "-C", dest, "remote", "get-url", "origin").Output()
+	if err != nil || strings.TrimSpace(string(origin)) != remote {
+		t.Errorf("Expected origin to point at %s, got %q (%v)", remote, origin, err)
+	}
+}
+
+// Test that git installs without repo or dest are rejected before running anything
+func TestGitInstaller_ShouldRequireRepoAndDest(t *testing.T) {
+	mockExecutor := &MockCommandExecutor{}
+	installer := &GitInstaller{CommandExecutor: mockExecutor}
+
+	err := installer.Install(gitTool("", "~/.tool", "", ""))
+
+	if err == nil {
+		t.Fatal("Expected error for missing repo")
+	}
+	if len(mockExecutor.ExecutedCommands) != 0 {
+		t.Errorf("Expected no commands, got: %v", mockExecutor.ExecutedCommands)
+	}
+}
@@ -1,11 +1,25 @@
 package installer
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/download"
+	"github.com/petersenjoern/devenv/internal/state"
 	"github.com/petersenjoern/devenv/internal/tui"
 )
 
@@ -13,30 +27,194 @@ type CommandExecutor interface {
 	Execute(command string) error
 }
 
-type RealCommandExecutor struct{}
+type RealCommandExecutor struct {
+	// Capture, when set, receives what commands print; otherwise their
+	// output is discarded.
+	Capture io.Writer
+	// Started, when set, is called with every command before it runs.
+	Started func(command string)
+	// Logger receives every command and its outcome at debug level;
+	// defaults to slog.Default().
+	Logger *slog.Logger
+}
 
 func (r *RealCommandExecutor) Execute(command string) error {
+	if r.Started != nil {
+		r.Started(command)
+	}
+	logger := loggerOr(r.Logger)
+	logger.Debug("running command", "command", command)
+
+	started := time.Now()
 	cmd := exec.Command("sh", "-c", command)
-	return cmd.Run()
+	if r.Capture != nil {
+		cmd.Stdout, cmd.Stderr = r.Capture, r.Capture
+	}
+	err := cmd.Run()
+
+	logger.Debug("command finished", "command", command, "duration", time.Since(started), "error", err)
+	return err
+}
+
+// loggerOr returns logger, or the default logger when it is nil.
+func loggerOr(logger *slog.Logger) *slog.Logger {
+	if logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+func (r *RealCommandExecutor) Output(command string) (string, error) {
+	output, err := exec.Command("sh", "-c", command).Output()
+	return string(output), err
 }
 
 type Installer interface {
 	Install(tool config.ToolConfig) error
 }
 
+// PackageSource provides local .deb files, e.g. from an offline bundle. The
+// files of a package include those of its dependencies.
+type PackageSource interface {
+	DebPaths(packageName string) ([]string, bool)
+}
+
 type APTInstaller struct {
 	CommandExecutor CommandExecutor
+	// Packages, when set, installs from local .deb files only.
+	Packages PackageSource
+	// UserMode reports apt tools as unavailable instead of invoking sudo.
+	UserMode bool
+	// Downloader fetches apt repository signing keys.
+	Downloader download.Downloader
+	// Arch is the repository architecture; defaults to the running binary's.
+	Arch string
+	// KeyringDir and SourcesDir default to /etc/apt/keyrings and
+	// /etc/apt/sources.list.d.
+	KeyringDir string
+	SourcesDir string
+	// Codename returns the distribution codename for {codename} in suites;
+	// defaults to VERSION_CODENAME from /etc/os-release.
+	Codename func() (string, error)
 }
 
+// AssetResolver maps a script path from the catalog to a file on disk,
+// e.g. by extracting the copy embedded in the binary.
+type AssetResolver interface {
+	Resolve(name string) (string, error)
+}
+
+// ScriptInstaller runs install scripts. Scripts that declare lifecycle
+// verbs in a "# devenv-verbs:" header are called with the verb as their
+// first argument; others are only run to install.
 type ScriptInstaller struct {
 	CommandExecutor CommandExecutor
+	Assets          AssetResolver
+	// Env is passed to every install script, e.g. DEVENV_BUNDLE_DIR.
+	Env map[string]string
+	// Probe runs the check and version verbs, which need the exit code and
+	// output; defaults to running them directly.
+	Probe OutputExecutor
+	// Library is the helper library sourced into every script, resolved
+	// like the scripts themselves.
+	Library string
+	// Logger receives what scripts log through the helper library; defaults
+	// to slog.Default().
+	Logger *slog.Logger
+}
+
+// OutputExecutor runs a command and returns its standard output.
+type OutputExecutor interface {
+	Output(command string) (string, error)
+}
+
+type DownloadInstaller struct {
+	CommandExecutor CommandExecutor
+	Downloader      download.Downloader
+	// Arch selects the per-architecture URL; defaults to the running binary's.
+	Arch string
+	// UserPrefix, when set, installs into UserPrefix/bin without sudo.
+	UserPrefix string
+	// Journal, when set, records the placed binary for rollback.
+	Journal ChangeRecorder
+}
+
+// ProgressTracker persists which tools of a plan are finished so an
+// interrupted run can be resumed.
+type ProgressTracker interface {
+	IsDone(toolName string) bool
+	MarkDone(toolName string) error
+}
+
+// MethodRecorder remembers which install method installed each tool, and in
+// which mode, so uninstall and upgrade act on the method of a fallback chain
+// that succeeded.
+type MethodRecorder interface {
+	Method(toolName string) string
+	RecordMethod(toolName, method string, userMode bool) error
+	ForgetMethod(toolName string) error
+}
+
+// ChangeRecorder journals the files devenv writes itself so a run can be
+// rolled back.
+type ChangeRecorder interface {
+	Backup(path string) (string, error)
+	Record(change state.Change) error
+}
+
+// ErrUnavailableInUserMode marks tools whose install method needs root and
+// therefore cannot be used in rootless --user mode.
+var ErrUnavailableInUserMode = errors.New("unavailable in user mode (requires root)")
+
+// ErrUninstallUnsupported marks tools whose install method cannot be
+// reversed automatically.
+var ErrUninstallUnsupported = errors.New("uninstall not supported for this install method")
+
+// ErrUpgradeUnsupported marks tools whose install method cannot be upgraded
+// automatically.
+var ErrUpgradeUnsupported = errors.New("upgrade not supported for this install method")
+
+// ErrManualActionPending marks tools whose manual installation has not been
+// confirmed yet, either because devenv runs non-interactively or because the
+// user chose to skip the step.
+var ErrManualActionPending = errors.New("pending manual action")
+
+// ErrCancelled marks tools whose installation was interrupted or never
+// started because the run was cancelled.
+var ErrCancelled = errors.New("installation cancelled")
+
+// ErrMethodUnavailable marks install methods of a fallback chain that cannot
+// be used on this system or are not configured for the tool.
+var ErrMethodUnavailable = errors.New("install method unavailable")
+
+// ManualPrompter asks the user to complete a manual installation.
+type ManualPrompter interface {
+	// AwaitCompletion blocks until the user confirms the installation is
+	// done (true) or chooses to skip it (false).
+	AwaitCompletion(tool config.ToolConfig) (bool, error)
+}
+
+// BinaryDetector verifies that a binary is available after installation.
+type BinaryDetector interface {
+	IsBinaryInstalled(binaryName string) bool
 }
 
-type ManualInstaller struct{}
+// ManualInstaller displays instructions for tools that cannot be installed
+// automatically. Without a Prompter it runs non-interactively and reports the
+// tool as pending; with one it waits for the user and verifies the result.
+type ManualInstaller struct {
+	Prompter ManualPrompter
+	Detector BinaryDetector
+	// Logger defaults to slog.Default().
+	Logger *slog.Logger
+	// Notify reports the instructions; they are printed when unset.
+	Notify func(format string, args ...any)
+}
 
 func NewAPTInstaller() *APTInstaller {
 	return &APTInstaller{
 		CommandExecutor: &RealCommandExecutor{},
+		Downloader:      download.NewHTTPDownloader(),
 	}
 }
 
@@ -46,18 +224,53 @@ func NewScriptInstaller() *ScriptInstaller {
 	}
 }
 
+func NewDownloadInstaller() *DownloadInstaller {
+	return &DownloadInstaller{
+		CommandExecutor: &RealCommandExecutor{},
+		Downloader:      download.NewHTTPDownloader(),
+	}
+}
+
 const (
-	aptUpdateCmd     = "sudo apt update"
-	aptInstallCmd    = "sudo apt install -y %s"
-	scriptInstallCmd = "bash %s"
+	aptUpdateCmd           = "sudo apt update"
+	aptInstallCmd          = "sudo apt install -y %s"
+	aptUpgradeCmd          = "sudo apt install --only-upgrade -y %s"
+	downloadInstallCmd     = "sudo install -m 0755 %s %s"
+	userDownloadInstallCmd = "install -D -m 0755 %s %s"
+	downloadRemoveCmd      = "sudo rm -f %s"
+	userDownloadRemoveCmd  = "rm -f %s"
+
+	defaultInstallDir = "/usr/local/bin"
+	defaultRetryDelay = 2 * time.Second
+	// defaultOutputExcerpt is how much of a tool's output results keep.
+	defaultOutputExcerpt = 4096
 
 	manualInstallMsg      = "Manual installation required for %s (%s)"
 	manualInstructionsMsg = "Installation instructions:\n%s"
 	manualFallbackMsg     = "No specific installation instructions provided. Please install %s manually."
 	manualVerifyMsg       = "Please complete the installation manually and run 'devenv status' to verify."
+	retryMsg              = "%s failed: %v; retrying in %s (attempt %d of %d)\n"
+	progressErrorMsg      = "Warning: failed to record progress for %s: %v\n"
+	methodErrorMsg        = "Warning: failed to record install method of %s: %v\n"
+	fallbackMsg           = "%s could not be installed via %s: %v\n"
+	manualNotDetectedMsg  = "%s was not detected (binary '%s' not found in PATH). Complete the installation or skip it."
 )
 
 func (a *APTInstaller) Install(tool config.ToolConfig) error {
+	if a.UserMode {
+		return fmt.Errorf("apt package %s: %w", tool.PackageName, ErrUnavailableInUserMode)
+	}
+
+	if a.Packages != nil {
+		return a.installLocalPackages(tool)
+	}
+
+	if tool.AptRepository != nil {
+		if err := a.setupRepository(tool.AptRepository); err != nil {
+			return err
+		}
+	}
+
 	if err := a.CommandExecutor.Execute(aptUpdateCmd); err != nil {
 		return fmt.Errorf("failed to update package list: %w", err)
 	}
@@ -70,60 +283,581 @@ func (a *APTInstaller) Install(tool config.ToolConfig) error {
 	return nil
 }
 
-func (s *ScriptInstaller) Install(tool config.ToolConfig) error {
-	if tool.InstallScript == "" {
-		return fmt.Errorf("install script path is required for script installation method")
+// Upgrade upgrades the tool's packages if a newer version is available.
+func (a *APTInstaller) Upgrade(tool config.ToolConfig) error {
+	if a.UserMode {
+		return fmt.Errorf("apt package %s: %w", tool.PackageName, ErrUnavailableInUserMode)
+	}
+
+	if err := a.CommandExecutor.Execute(aptUpdateCmd); err != nil {
+		return fmt.Errorf("failed to update package list: %w", err)
+	}
+
+	upgradeCmd := fmt.Sprintf(aptUpgradeCmd, tool.PackageName)
+	if err := a.CommandExecutor.Execute(upgradeCmd); err != nil {
+		return fmt.Errorf("failed to upgrade package %s: %w", tool.PackageName, err)
+	}
+
+	return nil
+}
+
+// Uninstall removes the tool's packages and, if it has one, its apt
+// repository and signing key.
+func (a *APTInstaller) Uninstall(tool config.ToolConfig) error {
+	if a.UserMode {
+		return fmt.Errorf("apt package %s: %w", tool.PackageName, ErrUnavailableInUserMode)
+	}
+
+	removeCmd := fmt.Sprintf(aptRemoveCmd, tool.PackageName)
+	if err := a.CommandExecutor.Execute(removeCmd); err != nil {
+		return fmt.Errorf("failed to remove package %s: %w", tool.PackageName, err)
+	}
+
+	if tool.AptRepository != nil {
+		return a.removeRepository(tool.AptRepository)
+	}
+	return nil
+}
+
+// installLocalPackages installs the tool's packages from .deb files without
+// contacting any apt mirror.
+func (a *APTInstaller) installLocalPackages(tool config.ToolConfig) error {
+	var debs []string
+	for _, packageName := range strings.Fields(tool.PackageName) {
+		debPaths, found := a.Packages.DebPaths(packageName)
+		if !found {
+			return fmt.Errorf("package %s not found in local package source", packageName)
+		}
+		for _, debPath := range debPaths {
+			if deb := shellQuote(debPath); !slices.Contains(debs, deb) {
+				debs = append(debs, deb)
+			}
+		}
+	}
+
+	if len(debs) == 0 {
+		return fmt.Errorf("package name is required for apt installation method")
+	}
+
+	installCmd := fmt.Sprintf(aptInstallCmd, strings.Join(debs, " "))
+	if err := a.CommandExecutor.Execute(installCmd); err != nil {
+		return fmt.Errorf("failed to install package %s from local files: %w", tool.PackageName, err)
+	}
+
+	return nil
+}
+
+func (d *DownloadInstaller) Install(tool config.ToolConfig) error {
+	if tool.DownloadURL == "" && len(tool.DownloadURLs) == 0 {
+		return fmt.Errorf("download URL is required for download installation method")
 	}
 
-	scriptCmd := fmt.Sprintf(scriptInstallCmd, tool.InstallScript)
-	if err := s.CommandExecutor.Execute(scriptCmd); err != nil {
-		return fmt.Errorf("failed to execute install script %s: %w", tool.InstallScript, err)
+	arch := hostArch(d.Arch)
+	url, err := config.ResolveDownloadURL(tool, arch)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "devenv-download-")
+	if err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	downloaded := filepath.Join(tmpDir, path.Base(url))
+	if err := d.Downloader.Download(url, config.ResolveChecksum(tool, arch), downloaded); err != nil {
+		return fmt.Errorf("failed to download %s: %w", tool.DisplayName, err)
+	}
+
+	location := installLocation(tool, d.UserPrefix)
+	command := downloadInstallCmd
+	if d.UserPrefix != "" {
+		command = userDownloadInstallCmd
+	}
+
+	if _, err := recordFileChange(d.Journal, tool.BinaryName, location, d.UserPrefix == ""); err != nil {
+		return err
+	}
+
+	installCmd := fmt.Sprintf(command, shellQuote(downloaded), shellQuote(location))
+	if err := d.CommandExecutor.Execute(installCmd); err != nil {
+		return fmt.Errorf("failed to install %s to %s: %w", tool.DisplayName, location, err)
 	}
 
 	return nil
 }
 
+// Uninstall removes the binary placed by Install.
+func (d *DownloadInstaller) Uninstall(tool config.ToolConfig) error {
+	location := installLocation(tool, d.UserPrefix)
+	command := downloadRemoveCmd
+	if d.UserPrefix != "" {
+		command = userDownloadRemoveCmd
+	}
+
+	if err := d.CommandExecutor.Execute(fmt.Sprintf(command, shellQuote(location))); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", location, err)
+	}
+	return nil
+}
+
+// hostArch returns arch, or the architecture devenv runs on when unset.
+func hostArch(arch string) string {
+	if arch == "" {
+		return config.NormalizeArch(runtime.GOARCH)
+	}
+	return config.NormalizeArch(arch)
+}
+
+// recordFileChange journals that path is about to be written, backing up
+// its current content first if it exists.
+func recordFileChange(journal ChangeRecorder, toolName, path string, privileged bool) (state.Change, error) {
+	change := state.Change{Tool: toolName, Kind: state.ChangeCreated, Path: path, Privileged: privileged}
+	if journal == nil {
+		return change, nil
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		backup, err := journal.Backup(path)
+		if err != nil {
+			return change, err
+		}
+		change.Kind, change.Backup, change.Mode = state.ChangeReplaced, backup, info.Mode()
+	}
+
+	if err := journal.Record(change); err != nil {
+		return change, fmt.Errorf("failed to journal %s: %w", path, err)
+	}
+	return change, nil
+}
+
+// installLocation returns where a downloaded binary is placed. With a user
+// prefix the binary keeps its file name but moves into userPrefix/bin.
+func installLocation(tool config.ToolConfig, userPrefix string) string {
+	location := filepath.Join(defaultInstallDir, tool.BinaryName)
+	if tool.InstallLocation != "" {
+		location = tool.InstallLocation
+	}
+
+	if userPrefix != "" {
+		return filepath.Join(userPrefix, "bin", filepath.Base(location))
+	}
+	return location
+}
+
+// envPrefix renders environment assignments for a shell command line in a
+// stable order.
+func envPrefix(env map[string]string) string {
+	if len(env) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var prefix strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&prefix, "%s=%s ", key, shellQuote(env[key]))
+	}
+	return prefix.String()
+}
+
+// shellQuote quotes s for use as a single word in a POSIX shell command.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./:=@%+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func (m *ManualInstaller) Install(tool config.ToolConfig) error {
-	fmt.Printf(manualInstallMsg+"\n", tool.DisplayName, tool.BinaryName)
+	logger := loggerOr(m.Logger).With("tool", tool.DisplayName)
+	logger.Info("manual installation required", "interactive", m.Prompter != nil)
+
+	notice(m.Notify, manualInstallMsg+"\n", tool.DisplayName, tool.BinaryName)
 
 	if tool.WSLNotes != "" {
-		fmt.Printf(manualInstructionsMsg+"\n", tool.WSLNotes)
+		notice(m.Notify, manualInstructionsMsg+"\n", tool.WSLNotes)
 	} else {
-		fmt.Printf(manualFallbackMsg+"\n", tool.DisplayName)
+		notice(m.Notify, manualFallbackMsg+"\n", tool.DisplayName)
 	}
 
-	fmt.Println(manualVerifyMsg)
-	return nil
+	if m.Prompter == nil {
+		notice(m.Notify, manualVerifyMsg+"\n")
+		return ErrManualActionPending
+	}
+
+	for {
+		completed, err := m.Prompter.AwaitCompletion(tool)
+		if err != nil {
+			return fmt.Errorf("failed to confirm manual installation of %s: %w", tool.DisplayName, err)
+		}
+
+		if !completed {
+			logger.Info("manual installation skipped by user")
+			return fmt.Errorf("%w: skipped by user", ErrManualActionPending)
+		}
+
+		if m.Detector == nil || m.Detector.IsBinaryInstalled(tool.BinaryName) {
+			logger.Info("manual installation confirmed")
+			return nil
+		}
+
+		logger.Warn("manual installation not detected", "binary", tool.BinaryName)
+
+		notice(m.Notify, manualNotDetectedMsg+"\n", tool.DisplayName, tool.BinaryName)
+	}
+}
+
+// notice reports a notice through notify, or prints it when notify is nil.
+func notice(notify func(format string, args ...any), format string, args ...any) {
+	if notify == nil {
+		fmt.Printf(format, args...)
+		return
+	}
+	notify(format, args...)
 }
 
 type InstallationOrchestrator struct {
-	APTInstaller    *APTInstaller
-	ScriptInstaller *ScriptInstaller
-	ManualInstaller *ManualInstaller
+	APTInstaller      *APTInstaller
+	ScriptInstaller   *ScriptInstaller
+	ManualInstaller   *ManualInstaller
+	DownloadInstaller *DownloadInstaller
+	GitInstaller      *GitInstaller
+	StepInstaller     *StepInstaller
+	// ConfigInstaller, when set, writes config templates after successful installs.
+	ConfigInstaller *ConfigInstaller
+	// CommandExecutor runs the post_install commands of installed tools.
+	CommandExecutor CommandExecutor
+	// Progress, when set, skips tools finished by an earlier attempt and
+	// records each tool that finishes now.
+	Progress ProgressTracker
+	// Methods, when set, records the method that installed each tool and
+	// is consulted by Uninstall and Upgrade.
+	Methods MethodRecorder
+	// UserMode is recorded with each installed tool, so uninstall and upgrade
+	// act on the copy in the user's prefix.
+	UserMode bool
+	// Arch is the target CPU architecture; defaults to the running binary's.
+	Arch string
+	// Retries is how often a failed install is retried unless the tool sets
+	// its own; each retry waits twice as long as the previous one, starting
+	// at RetryDelay. RetriesOverride makes Retries win over the tool's
+	// setting, e.g. when --retries was given explicitly.
+	Retries         int
+	RetriesOverride bool
+	RetryDelay      time.Duration
+	// Sleep waits between retries; defaults to time.Sleep.
+	Sleep func(time.Duration)
+	// MethodAvailable reports whether an install method can be used in the
+	// detected environment; fallback chains skip methods it rejects. All
+	// methods are considered available when unset.
+	MethodAvailable func(method string) bool
+	// Detect, when set, reports whether a tool is installed and its version.
+	// Tools that are already present are not installed again, and installed
+	// tools report the version found afterwards.
+	Detect func(tool config.ToolConfig) (installed bool, version string)
+	// Output, when set, collects what install commands print; each result
+	// keeps the tail of its tool's output.
+	Output *OutputTail
+	// Events, when set, receives the events of ExecuteInstallations and the
+	// notices otherwise printed.
+	Events *EventEmitter
+	// Logger receives the progress of every tool; defaults to
+	// slog.Default().
+	Logger *slog.Logger
+	// Cancelled reports whether the run was interrupted. The tool being
+	// installed at that point and all later ones are reported as cancelled.
+	Cancelled func() bool
 }
 
+// InstallStatus is the outcome of installing, upgrading or removing a tool.
+type InstallStatus string
+
+const (
+	StatusInstalled      InstallStatus = "installed"
+	StatusAlreadyPresent InstallStatus = "already-present"
+	StatusSkipped        InstallStatus = "skipped"
+	StatusFailed         InstallStatus = "failed"
+	StatusCancelled      InstallStatus = "cancelled"
+	StatusManualPending  InstallStatus = "manual-pending"
+	// StatusRemoved is reported by Uninstall.
+	StatusRemoved InstallStatus = "removed"
+)
+
 type InstallationResult struct {
-	Tool    config.ToolConfig
-	Success bool
-	Error   error
+	Tool   config.ToolConfig
+	Status InstallStatus
+	Error  error
+	// Order is the tool's position in the install plan.
+	Order      int
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Method is the install method that produced the result, for fallback
+	// chains the one that succeeded or was tried last.
+	Method string
+	// Version is the version detected after a successful install.
+	Version string
+	// Output is the tail of what the install commands printed.
+	Output string
+	// Attempts holds every try, the last one determining the outcome.
+	Attempts []Attempt
+	// Steps holds the per-step results of the last attempt of a steps
+	// pipeline.
+	Steps []StepResult
+}
+
+// Finished reports whether nothing is left to do for the tool in this run:
+// it is installed or cannot be installed here.
+func (r InstallationResult) Finished() bool {
+	switch r.Status {
+	case StatusInstalled, StatusAlreadyPresent, StatusSkipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// Duration is how long the tool took, from start to finish.
+func (r InstallationResult) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// InOrder returns the names of results in install order, falling back to
+// name order for results without one.
+func InOrder(results map[string]InstallationResult) []string {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := results[names[i]], results[names[j]]
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// OutputTail keeps the last Limit bytes written to it, so a result can show
+// what the commands of an install printed last.
+type OutputTail struct {
+	Limit int
+
+	mu   sync.Mutex
+	data []byte
+}
+
+func NewOutputTail() *OutputTail {
+	return &OutputTail{Limit: defaultOutputExcerpt}
+}
+
+func (t *OutputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.data = append(t.data, p...)
+	if t.Limit > 0 && len(t.data) > t.Limit {
+		t.data = t.data[len(t.data)-t.Limit:]
+	}
+	return len(p), nil
+}
+
+// Reset discards the collected output.
+func (t *OutputTail) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.data = nil
+}
+
+func (t *OutputTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.data)
+}
+
+// Attempt is a single try at installing a tool.
+type Attempt struct {
+	StartedAt time.Time
+	Duration  time.Duration
+	Error     error
+}
+
+// statusOf classifies the error of an install attempt.
+func statusOf(err error) InstallStatus {
+	switch {
+	case err == nil:
+		return StatusInstalled
+	case errors.Is(err, ErrManualActionPending):
+		return StatusManualPending
+	case errors.Is(err, ErrCancelled):
+		return StatusCancelled
+	case errors.Is(err, config.ErrUnsupportedArchitecture),
+		errors.Is(err, ErrUnavailableInUserMode),
+		errors.Is(err, ErrMethodUnavailable):
+		// Not available on this system: the architecture, rootless user mode
+		// or no method of the fallback chain applies.
+		return StatusSkipped
+	default:
+		return StatusFailed
+	}
 }
 
 func (o *InstallationOrchestrator) ExecuteInstallations(selections tui.Selections, tools map[string]config.ToolConfig) map[string]InstallationResult {
 	results := make(map[string]InstallationResult)
 
-	selectedTools := o.extractSelectedTools(selections)
+	installOrder := o.Plan(selections, tools)
+	o.Events.Emit(Event{Type: EventPlanResolved, Plan: installOrder})
 
-	installOrder := o.resolveDependencies(selectedTools, tools)
+	for order, toolName := range installOrder {
+		if o.Progress != nil && o.Progress.IsDone(toolName) {
+			continue
+		}
 
-	for _, toolName := range installOrder {
 		tool := tools[toolName]
-		result := o.installTool(tool)
+		o.Events.setTool(toolName)
+		var result InstallationResult
+		if o.cancelled() {
+			now := time.Now()
+			result = InstallationResult{Tool: tool, Status: StatusCancelled, Error: ErrCancelled, StartedAt: now, FinishedAt: now}
+		} else {
+			o.Events.Emit(Event{Type: EventToolStarted, DisplayName: tool.DisplayName, Method: tool.InstallMethod})
+			result = o.installTool(tool)
+		}
+		result.Order = order
 		results[toolName] = result
+		o.Events.flush()
+		o.Events.Emit(toolFinishedEvent(toolName, result))
+
+		if o.Progress != nil && result.Finished() {
+			if err := o.Progress.MarkDone(toolName); err != nil {
+				o.Notify(progressErrorMsg, toolName, err)
+			}
+		}
+		if o.Methods != nil && result.Status == StatusInstalled && result.Method != "" {
+			if err := o.Methods.RecordMethod(toolName, result.Method, o.UserMode); err != nil {
+				o.Notify(methodErrorMsg, toolName, err)
+			}
+		}
+	}
+
+	o.Events.setTool("")
+	summary := make(map[InstallStatus]int)
+	for _, result := range results {
+		summary[result.Status]++
+	}
+	o.Events.Emit(Event{Type: EventRunFinished, Summary: summary, Results: results})
+
+	return results
+}
+
+func toolFinishedEvent(toolName string, result InstallationResult) Event {
+	event := Event{
+		Type:        EventToolFinished,
+		Tool:        toolName,
+		DisplayName: result.Tool.DisplayName,
+		Status:      result.Status,
+		Method:      result.Method,
+		Version:     result.Version,
+		Duration:    result.Duration().Seconds(),
+	}
+	if result.Error != nil {
+		event.Error = result.Error.Error()
+	}
+	return event
+}
+
+// Notify reports a notice as a message event, or prints it without an
+// event sink.
+func (o *InstallationOrchestrator) Notify(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if o.Events == nil {
+		fmt.Print(message)
+		return
+	}
+	o.Events.Emit(Event{Type: EventMessage, Line: strings.TrimSuffix(message, "\n")})
+}
+
+// Uninstall removes the named tools in reverse order, so tools are removed
+// before the tools they were listed after. Dependencies are left installed.
+func (o *InstallationOrchestrator) Uninstall(toolNames []string, tools map[string]config.ToolConfig) map[string]InstallationResult {
+	results := make(map[string]InstallationResult)
+
+	for i := len(toolNames) - 1; i >= 0; i-- {
+		tool := o.installedMethod(toolNames[i], tools[toolNames[i]])
+
+		var err error
+		switch tool.InstallMethod {
+		case "apt":
+			err = o.APTInstaller.Uninstall(tool)
+		case "script":
+			err = o.ScriptInstaller.Uninstall(tool)
+		case "download":
+			err = o.DownloadInstaller.Uninstall(tool)
+		case "git":
+			err = o.GitInstaller.Uninstall(tool)
+		case "steps":
+			err = o.StepInstaller.Uninstall(tool)
+		default:
+			err = fmt.Errorf("%s (%s): %w", tool.DisplayName, tool.InstallMethod, ErrUninstallUnsupported)
+		}
+
+		status := StatusRemoved
+		if err != nil {
+			status = StatusFailed
+		}
+		results[toolNames[i]] = InstallationResult{Tool: tool, Status: status, Error: err, Method: tool.InstallMethod}
+
+		if o.Methods != nil && err == nil {
+			if err := o.Methods.ForgetMethod(toolNames[i]); err != nil {
+				o.Notify(methodErrorMsg, toolNames[i], err)
+			}
+		}
+	}
+
+	return results
+}
+
+// Upgrade upgrades the named tools in order. Scripts run their upgrade verb;
+// downloads, clones and pipelines are installed again, which picks up the
+// configured version.
+func (o *InstallationOrchestrator) Upgrade(toolNames []string, tools map[string]config.ToolConfig) map[string]InstallationResult {
+	results := make(map[string]InstallationResult)
+
+	for _, toolName := range toolNames {
+		tool := o.installedMethod(toolName, tools[toolName])
+
+		var err error
+		switch tool.InstallMethod {
+		case "apt":
+			err = o.APTInstaller.Upgrade(tool)
+		case "script":
+			err = o.ScriptInstaller.Upgrade(tool)
+		case "download", "git", "steps":
+			_, err = o.runInstaller(tool)
+		default:
+			err = fmt.Errorf("%s (%s): %w", tool.DisplayName, tool.InstallMethod, ErrUpgradeUnsupported)
+		}
+
+		status := StatusInstalled
+		if err != nil {
+			status = StatusFailed
+		}
+		results[toolName] = InstallationResult{Tool: tool, Status: status, Error: err, Method: tool.InstallMethod}
 	}
 
 	return results
 }
 
+// Plan returns the selected tools and their dependencies in installation order.
+func (o *InstallationOrchestrator) Plan(selections tui.Selections, tools map[string]config.ToolConfig) []string {
+	selectedTools := o.extractSelectedTools(selections)
+	return o.resolveDependencies(selectedTools, tools)
+}
+
 func (o *InstallationOrchestrator) extractSelectedTools(selections tui.Selections) []string {
 	var selectedTools []string
 	for _, categoryAndTools := range selections.CategoryAndTools {
@@ -171,22 +905,322 @@ func (o *InstallationOrchestrator) resolveDependencies(selectedTools []string, t
 }
 
 func (o *InstallationOrchestrator) installTool(tool config.ToolConfig) InstallationResult {
+	logger := o.log().With("tool", tool.DisplayName)
+	logger.Info("installing tool", "method", tool.InstallMethod, "methods", tool.InstallMethods)
+
+	started := time.Now()
+	result := o.install(tool)
+	result.StartedAt, result.FinishedAt = started, time.Now()
+
+	level := slog.LevelInfo
+	if result.Status == StatusFailed {
+		level = slog.LevelError
+	}
+	logger.Log(context.Background(), level, "tool finished",
+		"status", result.Status, "method", result.Method, "version", result.Version,
+		"attempts", len(result.Attempts), "duration", result.Duration(), "error", result.Error)
+	return result
+}
+
+func (o *InstallationOrchestrator) log() *slog.Logger {
+	return loggerOr(o.Logger)
+}
+
+func (o *InstallationOrchestrator) install(tool config.ToolConfig) InstallationResult {
+	arch := hostArch(o.Arch)
+	if !config.SupportsArchitecture(tool, arch) {
+		return InstallationResult{
+			Tool:   tool,
+			Status: StatusSkipped,
+			Method: tool.InstallMethod,
+			Error:  fmt.Errorf("%w: %s", config.ErrUnsupportedArchitecture, arch),
+		}
+	}
+
+	if o.Detect != nil {
+		if installed, version := o.Detect(tool); installed {
+			return InstallationResult{Tool: tool, Status: StatusAlreadyPresent, Version: version}
+		}
+	}
+
+	var result InstallationResult
+	if len(tool.InstallMethods) == 0 {
+		result = o.installWith(tool)
+	} else {
+		result = o.installWithFallback(tool, arch)
+	}
+
+	if result.Status == StatusFailed && o.cancelled() {
+		result.Status, result.Error = StatusCancelled, fmt.Errorf("%w: %w", ErrCancelled, result.Error)
+	}
+	if result.Status != StatusInstalled {
+		return result
+	}
+
+	if err := o.runPostInstall(tool); err != nil {
+		result.Status = StatusFailed
+		result.Error = fmt.Errorf("installed but post-install command failed: %w", err)
+		return result
+	}
+
+	if o.ConfigInstaller != nil {
+		if configErr := o.ConfigInstaller.Apply(tool); configErr != nil {
+			result.Status = StatusFailed
+			result.Error = fmt.Errorf("installed but failed to apply config: %w", configErr)
+			return result
+		}
+	}
+
+	if o.Detect != nil {
+		if installed, version := o.Detect(tool); installed {
+			result.Version = version
+		}
+	}
+
+	return result
+}
+
+// runPostInstall runs the tool's post_install commands in order and stops at
+// the first failure.
+func (o *InstallationOrchestrator) runPostInstall(tool config.ToolConfig) error {
+	if len(tool.PostInstall) == 0 {
+		return nil
+	}
+	if o.CommandExecutor == nil {
+		return errors.New("no command executor configured")
+	}
+
+	for _, command := range tool.PostInstall {
+		if err := o.CommandExecutor.Execute(command); err != nil {
+			return fmt.Errorf("%s: %w", command, err)
+		}
+	}
+	return nil
+}
+
+// installWithFallback tries the methods of tool's fallback chain in order,
+// skipping those that do not apply here, until one succeeds or waits on a
+// manual step.
+func (o *InstallationOrchestrator) installWithFallback(tool config.ToolConfig, arch string) InstallationResult {
+	var skipped, failed []error
+	var attempts []Attempt
+	var last InstallationResult
+
+	for _, method := range tool.InstallMethods {
+		candidate := tool
+		candidate.InstallMethod = method
+
+		if err := o.methodApplies(candidate, arch); err != nil {
+			o.log().Debug("skipping install method", "tool", tool.DisplayName, "method", method, "reason", err)
+			skipped = append(skipped, fmt.Errorf("%s: %w", method, err))
+			continue
+		}
+
+		result := o.installWith(candidate)
+		attempts = append(attempts, result.Attempts...)
+		result.Tool, result.Attempts = tool, attempts
+
+		if result.Status == StatusInstalled || result.Status == StatusManualPending {
+			return result
+		}
+		if result.Status == StatusSkipped {
+			skipped = append(skipped, fmt.Errorf("%s: %w", method, result.Error))
+			continue
+		}
+		if o.cancelled() {
+			return result
+		}
+
+		o.Notify(fallbackMsg, tool.DisplayName, method, result.Error)
+		failed = append(failed, fmt.Errorf("%s: %w", method, result.Error))
+		last = result
+	}
+
+	if len(failed) == 0 {
+		return InstallationResult{
+			Tool:     tool,
+			Status:   StatusSkipped,
+			Error:    fmt.Errorf("no applicable install method: %w", errors.Join(skipped...)),
+			Attempts: attempts,
+		}
+	}
+
+	last.Error = errors.Join(failed...)
+	return last
+}
+
+// installWith installs tool with its install method, retrying transient
+// failures.
+func (o *InstallationOrchestrator) installWith(tool config.ToolConfig) InstallationResult {
+	retries, delay := o.retryPolicy(tool)
+	if o.Output != nil {
+		o.Output.Reset()
+	}
+
 	var err error
+	var attempts []Attempt
+	var steps []StepResult
+	for attempt := 0; ; attempt++ {
+		started := time.Now()
+		steps, err = o.runInstaller(tool)
+		attempts = append(attempts, Attempt{StartedAt: started, Duration: time.Since(started), Error: err})
+		if err != nil {
+			o.log().Warn("install attempt failed", "tool", tool.DisplayName, "method", tool.InstallMethod, "attempt", attempt+1, "error", err)
+		}
+
+		if err == nil || attempt >= retries || !isRetryable(tool, err) || o.cancelled() {
+			break
+		}
+
+		backoff := delay << attempt
+		o.Notify(retryMsg, tool.DisplayName, err, backoff, attempt+2, retries+1)
+		o.sleep(backoff)
+	}
+
+	return InstallationResult{
+		Tool:     tool,
+		Status:   statusOf(err),
+		Error:    err,
+		Method:   tool.InstallMethod,
+		Output:   o.output(),
+		Attempts: attempts,
+		Steps:    steps,
+	}
+}
+
+func (o *InstallationOrchestrator) output() string {
+	if o.Output == nil {
+		return ""
+	}
+	return o.Output.String()
+}
+
+func (o *InstallationOrchestrator) cancelled() bool {
+	return o.Cancelled != nil && o.Cancelled()
+}
+
+// methodApplies reports why tool's install method cannot be used here, or
+// nil if it can.
+func (o *InstallationOrchestrator) methodApplies(tool config.ToolConfig, arch string) error {
+	if o.MethodAvailable != nil && !o.MethodAvailable(tool.InstallMethod) {
+		return fmt.Errorf("%w: not supported in this environment", ErrMethodUnavailable)
+	}
 
+	var configured bool
 	switch tool.InstallMethod {
 	case "apt":
-		err = o.APTInstaller.Install(tool)
+		if o.APTInstaller != nil && o.APTInstaller.UserMode {
+			return ErrUnavailableInUserMode
+		}
+		configured = tool.PackageName != ""
 	case "script":
-		err = o.ScriptInstaller.Install(tool)
+		configured = tool.InstallScript != ""
+	case "download":
+		if tool.DownloadURL == "" && len(tool.DownloadURLs) == 0 {
+			break
+		}
+		if _, err := config.ResolveDownloadURL(tool, arch); err != nil {
+			return err
+		}
+		configured = true
+	case "git":
+		configured = tool.Git != nil
+	case "steps":
+		configured = len(tool.Steps) > 0
 	case "manual":
-		err = o.ManualInstaller.Install(tool)
+		configured = true
 	default:
-		err = fmt.Errorf("unknown install method: %s", tool.InstallMethod)
+		return fmt.Errorf("unknown install method: %s", tool.InstallMethod)
 	}
 
-	return InstallationResult{
-		Tool:    tool,
-		Success: err == nil,
-		Error:   err,
+	if !configured {
+		return fmt.Errorf("%w: not configured", ErrMethodUnavailable)
+	}
+	return nil
+}
+
+// installedMethod returns tool with the method that installed it as its
+// install method, which is what uninstall and upgrade act on. Tools whose
+// method was not recorded fall back to primaryMethod.
+func (o *InstallationOrchestrator) installedMethod(toolName string, tool config.ToolConfig) config.ToolConfig {
+	if o.Methods != nil && len(tool.InstallMethods) > 0 {
+		if method := o.Methods.Method(toolName); slices.Contains(tool.InstallMethods, method) {
+			tool.InstallMethod = method
+			return tool
+		}
+	}
+	return o.primaryMethod(tool)
+}
+
+// primaryMethod returns tool with the first applicable method of its
+// fallback chain as its install method. Tools without a chain are returned
+// unchanged.
+func (o *InstallationOrchestrator) primaryMethod(tool config.ToolConfig) config.ToolConfig {
+	arch := hostArch(o.Arch)
+	for _, method := range tool.InstallMethods {
+		candidate := tool
+		candidate.InstallMethod = method
+		if o.methodApplies(candidate, arch) == nil {
+			return candidate
+		}
+	}
+	return tool
+}
+
+// runInstaller installs tool with its method's installer. Only steps
+// pipelines report per-step results.
+func (o *InstallationOrchestrator) runInstaller(tool config.ToolConfig) ([]StepResult, error) {
+	switch tool.InstallMethod {
+	case "apt":
+		return nil, o.APTInstaller.Install(tool)
+	case "script":
+		return nil, o.ScriptInstaller.Install(tool)
+	case "manual":
+		return nil, o.ManualInstaller.Install(tool)
+	case "download":
+		return nil, o.DownloadInstaller.Install(tool)
+	case "git":
+		return nil, o.GitInstaller.Install(tool)
+	case "steps":
+		return o.StepInstaller.Run(tool)
+	default:
+		return nil, fmt.Errorf("unknown install method: %s", tool.InstallMethod)
+	}
+}
+
+// retryPolicy returns how often a failed install is retried and the delay
+// before the first retry; per-tool settings override the orchestrator's
+// unless RetriesOverride is set.
+func (o *InstallationOrchestrator) retryPolicy(tool config.ToolConfig) (int, time.Duration) {
+	retries, delay := o.Retries, o.RetryDelay
+	if tool.Retries > 0 && !o.RetriesOverride {
+		retries = tool.Retries
+	}
+	if tool.RetryDelay > 0 {
+		delay = tool.RetryDelay
+	}
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+	return retries, delay
+}
+
+func (o *InstallationOrchestrator) sleep(d time.Duration) {
+	if o.Sleep != nil {
+		o.Sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// isRetryable reports whether a failure may be transient. Manual steps,
+// tools that cannot be installed in this mode and downloads that fail their
+// checksum are never retried.
+func isRetryable(tool config.ToolConfig, err error) bool {
+	switch tool.InstallMethod {
+	case "apt", "script", "download", "git", "steps":
+		return !errors.Is(err, ErrUnavailableInUserMode) && !errors.Is(err, download.ErrChecksumMismatch)
+	default:
+		return false
 	}
 }
@@ -139,3 +139,28 @@ func TestAPTInstaller_ShouldRunUpdateBeforeInstall(t *testing.T) {
 		}
 	}
 }
+
+func TestAPTInstaller_ShouldReportUnavailableInUserMode(t *testing.T) {
+	// Test that apt tools are not attempted without root in --user mode
+	mockExecutor := &MockCommandExecutor{}
+	installer := &APTInstaller{
+		CommandExecutor: mockExecutor,
+		UserMode:        true,
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Tmux Terminal Multiplexer",
+		BinaryName:    "tmux",
+		InstallMethod: "apt",
+		PackageName:   "tmux",
+	}
+
+	err := installer.Install(tool)
+	if !errors.Is(err, ErrUnavailableInUserMode) {
+		t.Errorf("Expected ErrUnavailableInUserMode, got: %v", err)
+	}
+
+	if len(mockExecutor.ExecutedCommands) != 0 {
+		t.Errorf("Expected no apt commands in user mode, got %v", mockExecutor.ExecutedCommands)
+	}
+}
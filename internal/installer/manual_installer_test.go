@@ -1,6 +1,7 @@
 package installer
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -23,9 +24,9 @@ func TestManualInstaller_ShouldDisplayInstallationInstructions(t *testing.T) {
 
 	err := installer.Install(tool)
 
-	// Should not return an error for valid manual installation
-	if err != nil {
-		t.Errorf("Expected ManualInstaller to handle installation instructions successfully, got error: %v", err)
+	// Without a prompter the installation stays pending rather than failing
+	if !errors.Is(err, ErrManualActionPending) {
+		t.Errorf("Expected ManualInstaller to report pending manual action, got: %v", err)
 	}
 
 }
@@ -46,8 +47,8 @@ func TestManualInstaller_ShouldHandleEmptyInstructions(t *testing.T) {
 
 	err := installer.Install(tool)
 
-	// Should not return an error - it should display fallback message instead
-	if err != nil {
+	// Should report pending manual action - it should display fallback message instead
+	if !errors.Is(err, ErrManualActionPending) {
 		t.Errorf("Expected ManualInstaller to handle empty instructions gracefully, got error: %v", err)
 	}
 
@@ -74,9 +75,9 @@ func TestManualInstaller_ShouldNotExecuteSystemCommands(t *testing.T) {
 
 	err := installer.Install(tool)
 
-	// Should succeed without executing any commands
-	if err != nil {
-		t.Errorf("Expected ManualInstaller to handle manual instructions without error, got: %v", err)
+	// Should stay pending without executing any commands
+	if !errors.Is(err, ErrManualActionPending) {
+		t.Errorf("Expected ManualInstaller to report pending manual action, got: %v", err)
 	}
 
 	// Verify that ManualInstaller struct doesn't have CommandExecutor field
@@ -89,3 +90,88 @@ func TestManualInstaller_ShouldNotExecuteSystemCommands(t *testing.T) {
 		}
 	}
 }
+
+type stubManualPrompter struct {
+	answers []bool
+	calls   int
+}
+
+func (s *stubManualPrompter) AwaitCompletion(tool config.ToolConfig) (bool, error) {
+	answer := s.answers[s.calls]
+	s.calls++
+	return answer, nil
+}
+
+type stubBinaryDetector struct {
+	installed map[string]bool
+}
+
+func (s *stubBinaryDetector) IsBinaryInstalled(binaryName string) bool {
+	return s.installed[binaryName]
+}
+
+func TestManualInstaller_ShouldSucceedWhenUserConfirmsAndBinaryIsDetected(t *testing.T) {
+	// Test that a confirmed manual installation is verified with the detector
+	prompter := &stubManualPrompter{answers: []bool{true}}
+	installer := &ManualInstaller{
+		Prompter: prompter,
+		Detector: &stubBinaryDetector{installed: map[string]bool{"alacritty": true}},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Alacritty Terminal",
+		BinaryName:    "alacritty",
+		InstallMethod: "manual",
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Errorf("Expected confirmed and detected installation to succeed, got: %v", err)
+	}
+
+	if prompter.calls != 1 {
+		t.Errorf("Expected user to be prompted once, got %d prompts", prompter.calls)
+	}
+}
+
+func TestManualInstaller_ShouldReportPendingWhenUserSkips(t *testing.T) {
+	// Test that skipping the manual step leaves the tool pending
+	installer := &ManualInstaller{
+		Prompter: &stubManualPrompter{answers: []bool{false}},
+		Detector: &stubBinaryDetector{},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "GlazeWM Tiling Manager",
+		BinaryName:    "glazewm",
+		InstallMethod: "manual",
+	}
+
+	err := installer.Install(tool)
+	if !errors.Is(err, ErrManualActionPending) {
+		t.Errorf("Expected pending manual action when user skips, got: %v", err)
+	}
+}
+
+func TestManualInstaller_ShouldPromptAgainWhenBinaryIsNotDetected(t *testing.T) {
+	// Test that an unverified confirmation asks the user again until they skip
+	prompter := &stubManualPrompter{answers: []bool{true, false}}
+	installer := &ManualInstaller{
+		Prompter: prompter,
+		Detector: &stubBinaryDetector{},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Alacritty Terminal",
+		BinaryName:    "alacritty",
+		InstallMethod: "manual",
+	}
+
+	err := installer.Install(tool)
+	if !errors.Is(err, ErrManualActionPending) {
+		t.Errorf("Expected pending manual action after skipping unverified installation, got: %v", err)
+	}
+
+	if prompter.calls != 2 {
+		t.Errorf("Expected user to be prompted twice, got %d prompts", prompter.calls)
+	}
+}
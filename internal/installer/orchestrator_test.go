@@ -1,10 +1,14 @@
 package installer
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/download"
+	"github.com/petersenjoern/devenv/internal/state"
 	"github.com/petersenjoern/devenv/internal/tui"
 )
 
@@ -207,7 +211,7 @@ func TestOrchestrator_ShouldCollectInstallationResults(t *testing.T) {
 	}
 
 	// Should indicate successful installation
-	if gitResult.Success != true {
+	if gitResult.Status != StatusInstalled {
 		t.Errorf("Expected git installation to succeed")
 	}
 
@@ -257,7 +261,7 @@ func TestOrchestrator_ShouldHandleInstallationFailures(t *testing.T) {
 	}
 
 	// Should indicate failed installation
-	if gitResult.Success != false {
+	if gitResult.Status != StatusFailed {
 		t.Errorf("Expected git installation to fail")
 	}
 
@@ -268,7 +272,7 @@ func TestOrchestrator_ShouldHandleInstallationFailures(t *testing.T) {
 }
 
 func TestOrchestrator_ShouldHandleManualInstallations(t *testing.T) {
-	// Test that manual installations are handled (display messages, always succeed)
+	// Test that non-interactive manual installations are reported as pending
 	orchestrator := &InstallationOrchestrator{
 		APTInstaller:    &APTInstaller{CommandExecutor: &MockCommandExecutor{}},
 		ScriptInstaller: &ScriptInstaller{CommandExecutor: &MockCommandExecutor{}},
@@ -302,8 +306,509 @@ func TestOrchestrator_ShouldHandleManualInstallations(t *testing.T) {
 		t.Errorf("Expected result for alacritty manual installation")
 	}
 
-	// Manual installations should always succeed (they just display instructions)
-	if alacrittyResult.Success != true {
-		t.Errorf("Expected manual installation to succeed")
+	// Manual installations without confirmation are neither successful nor failed
+	if alacrittyResult.Status == StatusInstalled {
+		t.Errorf("Expected unconfirmed manual installation not to be reported as successful")
+	}
+
+	if alacrittyResult.Status != StatusManualPending {
+		t.Errorf("Expected manual installation to be pending, got error: %v", alacrittyResult.Error)
+	}
+}
+
+func TestOrchestrator_ShouldReportUnsupportedArchitecture(t *testing.T) {
+	// Test that tools unavailable for the architecture are reported without running installers
+	mockExecutor := &MockCommandExecutor{}
+	orchestrator := &InstallationOrchestrator{
+		APTInstaller:    &APTInstaller{CommandExecutor: mockExecutor},
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: mockExecutor},
+		ManualInstaller: &ManualInstaller{},
+		Arch:            "arm64",
+	}
+
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{
+			{Category: "utilities", Tools: []string{"x86-only"}},
+		},
+	}
+
+	tools := map[string]config.ToolConfig{
+		"x86-only": {
+			DisplayName:   "x86 Only Tool",
+			BinaryName:    "x86-only",
+			InstallMethod: "script",
+			InstallScript: "install_scripts/x86-only.sh",
+			Architectures: []string{"amd64"},
+		},
+	}
+
+	results := orchestrator.ExecuteInstallations(selections, tools)
+
+	result := results["x86-only"]
+	if result.Status != StatusSkipped {
+		t.Errorf("Expected unsupported architecture result, got %s: %v", result.Status, result.Error)
+	}
+
+	if len(mockExecutor.ExecutedCommands) != 0 {
+		t.Errorf("Expected no commands for unsupported tool, got %v", mockExecutor.ExecutedCommands)
+	}
+}
+
+type stubProgress struct {
+	done   map[string]bool
+	marked []string
+}
+
+func (s *stubProgress) IsDone(toolName string) bool { return s.done[toolName] }
+
+func (s *stubProgress) MarkDone(toolName string) error {
+	s.marked = append(s.marked, toolName)
+	return nil
+}
+
+func TestOrchestrator_ShouldSkipFinishedToolsWhenResuming(t *testing.T) {
+	// Test that a resumed run continues from the first unfinished tool
+	mockExecutor := &MockCommandExecutor{}
+	progress := &stubProgress{done: map[string]bool{"curl": true}}
+	orchestrator := &InstallationOrchestrator{
+		APTInstaller:    &APTInstaller{CommandExecutor: mockExecutor},
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: mockExecutor},
+		ManualInstaller: &ManualInstaller{},
+		Progress:        progress,
+	}
+
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{
+			{Category: "resume", Tools: []string{"curl", "docker"}},
+		},
+	}
+
+	tools := map[string]config.ToolConfig{
+		"curl":   {BinaryName: "curl", InstallMethod: "apt", PackageName: "curl"},
+		"docker": {BinaryName: "docker", InstallMethod: "script", InstallScript: "install_scripts/docker.sh"},
+	}
+
+	results := orchestrator.ExecuteInstallations(selections, tools)
+
+	if _, found := results["curl"]; found {
+		t.Errorf("Expected finished tool curl to be skipped")
+	}
+
+	expected := []string{"bash install_scripts/docker.sh"}
+	if len(mockExecutor.ExecutedCommands) != 1 || mockExecutor.ExecutedCommands[0] != expected[0] {
+		t.Errorf("Expected only docker to be installed, got %v", mockExecutor.ExecutedCommands)
+	}
+
+	if len(progress.marked) != 1 || progress.marked[0] != "docker" {
+		t.Errorf("Expected docker to be marked done, got %v", progress.marked)
+	}
+}
+
+// flakyExecutor fails the first failures commands, then succeeds.
+type flakyExecutor struct {
+	MockCommandExecutor
+	failures int
+}
+
+func (f *flakyExecutor) Execute(command string) error {
+	f.ExecutedCommands = append(f.ExecutedCommands, command)
+	if len(f.ExecutedCommands) <= f.failures {
+		return fmt.Errorf("temporary failure resolving archive.ubuntu.com")
+	}
+	return nil
+}
+
+func TestOrchestrator_ShouldRetryWithExponentialBackoff(t *testing.T) {
+	// Test that transient failures are retried with doubling delays and each attempt is recorded
+	executor := &flakyExecutor{failures: 2}
+	var delays []time.Duration
+	orchestrator := &InstallationOrchestrator{
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: executor},
+		Retries:         1,
+		RetryDelay:      time.Second,
+		Sleep:           func(d time.Duration) { delays = append(delays, d) },
+	}
+
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{
+			{Category: "development", Tools: []string{"github_cli"}},
+		},
+	}
+
+	tools := map[string]config.ToolConfig{
+		"github_cli": {
+			DisplayName:   "GitHub CLI",
+			BinaryName:    "gh",
+			InstallMethod: "script",
+			InstallScript: "install_scripts/github-cli.sh",
+			Retries:       3, // overrides the orchestrator default
+		},
+	}
+
+	result := orchestrator.ExecuteInstallations(selections, tools)["github_cli"]
+
+	if result.Status != StatusInstalled {
+		t.Fatalf("Expected install to succeed on third attempt, got: %v", result.Error)
+	}
+
+	if len(result.Attempts) != 3 || result.Attempts[0].Error == nil || result.Attempts[2].Error != nil {
+		t.Errorf("Expected two failed attempts and one success, got %+v", result.Attempts)
+	}
+
+	expectedDelays := []time.Duration{time.Second, 2 * time.Second}
+	if len(delays) != len(expectedDelays) || delays[0] != expectedDelays[0] || delays[1] != expectedDelays[1] {
+		t.Errorf("Expected backoff delays %v, got %v", expectedDelays, delays)
+	}
+}
+
+func TestOrchestrator_ShouldNotRetryManualInstallations(t *testing.T) {
+	orchestrator := &InstallationOrchestrator{
+		ManualInstaller: &ManualInstaller{},
+		Retries:         3,
+		Sleep:           func(time.Duration) { t.Errorf("Expected no retry for manual tool") },
+	}
+
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{{Category: "terminals", Tools: []string{"alacritty"}}},
+	}
+	tools := map[string]config.ToolConfig{
+		"alacritty": {DisplayName: "Alacritty Terminal", BinaryName: "alacritty", InstallMethod: "manual"},
+	}
+
+	result := orchestrator.ExecuteInstallations(selections, tools)["alacritty"]
+	if len(result.Attempts) != 1 {
+		t.Errorf("Expected a single attempt, got %d", len(result.Attempts))
+	}
+}
+
+func TestOrchestrator_ShouldPreferExplicitRetriesOverToolSetting(t *testing.T) {
+	// Test that an explicit --retries wins over the tool's own retries setting
+	executor := &MockCommandExecutor{ShouldFail: true, FailureError: fmt.Errorf("temporary failure")}
+	orchestrator := &InstallationOrchestrator{
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: executor},
+		Retries:         1,
+		RetriesOverride: true,
+		Sleep:           func(time.Duration) {},
+	}
+
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{{Category: "development", Tools: []string{"github_cli"}}},
+	}
+	tools := map[string]config.ToolConfig{
+		"github_cli": {DisplayName: "GitHub CLI", InstallMethod: "script", InstallScript: "install_scripts/github-cli.sh", Retries: 5},
+	}
+
+	result := orchestrator.ExecuteInstallations(selections, tools)["github_cli"]
+	if len(result.Attempts) != 2 {
+		t.Errorf("Expected one retry from --retries, got %d attempts", len(result.Attempts))
+	}
+}
+
+func TestOrchestrator_ShouldNotRetryChecksumMismatches(t *testing.T) {
+	// Test that a download failing its checksum is not downloaded again
+	executor := &MockCommandExecutor{
+		ShouldFail:   true,
+		FailureError: fmt.Errorf("failed to fetch bat.deb: %w", download.ErrChecksumMismatch),
+	}
+	orchestrator := &InstallationOrchestrator{
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: executor},
+		Retries:         3,
+		Sleep:           func(time.Duration) { t.Errorf("Expected no retry after a checksum mismatch") },
+	}
+
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{{Category: "development", Tools: []string{"bat"}}},
+	}
+	tools := map[string]config.ToolConfig{
+		"bat": {DisplayName: "bat", InstallMethod: "script", InstallScript: "install_scripts/bat.sh"},
+	}
+
+	result := orchestrator.ExecuteInstallations(selections, tools)["bat"]
+	if result.Status != StatusFailed || len(result.Attempts) != 1 {
+		t.Errorf("Expected a single failed attempt, got %s after %d attempts", result.Status, len(result.Attempts))
+	}
+}
+
+func TestOrchestrator_ShouldUpgradeByInstallMethod(t *testing.T) {
+	// Test that upgrade uses apt's only-upgrade and reports manual tools as unsupported
+	mockExecutor := &MockCommandExecutor{}
+	orchestrator := &InstallationOrchestrator{
+		APTInstaller:    &APTInstaller{CommandExecutor: mockExecutor},
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: mockExecutor},
+	}
+	tools := map[string]config.ToolConfig{
+		"git":  {DisplayName: "Git", InstallMethod: "apt", PackageName: "git"},
+		"code": {DisplayName: "VS Code", InstallMethod: "manual"},
+	}
+
+	results := orchestrator.Upgrade([]string{"git", "code"}, tools)
+
+	if results["git"].Status != StatusInstalled {
+		t.Errorf("Expected git upgrade to succeed, got: %v", results["git"].Error)
+	}
+	if len(mockExecutor.ExecutedCommands) != 2 || mockExecutor.ExecutedCommands[1] != "sudo apt install --only-upgrade -y git" {
+		t.Errorf("Expected apt update and only-upgrade, got %v", mockExecutor.ExecutedCommands)
+	}
+	if !errors.Is(results["code"].Error, ErrUpgradeUnsupported) {
+		t.Errorf("Expected ErrUpgradeUnsupported for manual tool, got: %v", results["code"].Error)
+	}
+}
+
+func TestOrchestrator_ShouldFallBackToNextInstallMethod(t *testing.T) {
+	// Test that a failed method falls through to the next one and the successful method is recorded
+	aptExecutor := &MockCommandExecutor{ShouldFail: true, FailureError: errors.New("apt broken")}
+	scriptExecutor := &MockCommandExecutor{}
+	orchestrator := &InstallationOrchestrator{
+		APTInstaller:    &APTInstaller{CommandExecutor: aptExecutor},
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: scriptExecutor},
+	}
+	tool := config.ToolConfig{
+		DisplayName:    "Bat",
+		BinaryName:     "bat",
+		InstallMethods: []string{"apt", "script"},
+		PackageName:    "bat",
+		InstallScript:  "install_scripts/bat.sh",
+	}
+
+	result := orchestrator.installTool(tool)
+
+	if result.Status != StatusInstalled {
+		t.Fatalf("Expected fallback to script to succeed, got: %v", result.Error)
+	}
+	if result.Method != "script" {
+		t.Errorf("Expected script to be recorded as the method, got %q", result.Method)
+	}
+	if len(result.Attempts) != 2 || result.Attempts[0].Error == nil {
+		t.Errorf("Expected the failed apt attempt to be kept, got %+v", result.Attempts)
+	}
+	if len(scriptExecutor.ExecutedCommands) != 1 {
+		t.Errorf("Expected the script to run once, got %v", scriptExecutor.ExecutedCommands)
+	}
+}
+
+func TestOrchestrator_ShouldUninstallWithTheMethodThatInstalled(t *testing.T) {
+	// Test that the method recorded by a fallback install is used by a later uninstall
+	stateDir := t.TempDir()
+	aptExecutor := &MockCommandExecutor{ShouldFail: true, FailureError: errors.New("apt broken")}
+	downloadExecutor := &MockCommandExecutor{}
+	newOrchestrator := func() *InstallationOrchestrator {
+		methods, err := state.LoadMethods(stateDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &InstallationOrchestrator{
+			APTInstaller:      &APTInstaller{CommandExecutor: aptExecutor},
+			DownloadInstaller: &DownloadInstaller{CommandExecutor: downloadExecutor, Downloader: &stubDownloader{}, UserPrefix: t.TempDir()},
+			Arch:              "amd64",
+			Methods:           methods,
+		}
+	}
+	tools := map[string]config.ToolConfig{
+		"bat": {
+			DisplayName:    "Bat",
+			BinaryName:     "bat",
+			InstallMethods: []string{"apt", "download"},
+			PackageName:    "bat",
+			DownloadURLs:   map[string]string{"amd64": "https://example.com/bat"},
+		},
+	}
+	selections := tui.Selections{CategoryAndTools: []tui.CategoryAndTools{{Category: "utilities", Tools: []string{"bat"}}}}
+
+	if result := newOrchestrator().ExecuteInstallations(selections, tools)["bat"]; result.Method != "download" {
+		t.Fatalf("Expected bat to be installed via download, got %q: %v", result.Method, result.Error)
+	}
+
+	aptExecutor.ExecutedCommands = nil
+	aptExecutor.ShouldFail = false
+	result := newOrchestrator().Uninstall([]string{"bat"}, tools)["bat"]
+
+	if result.Method != "download" {
+		t.Errorf("Expected uninstall via download, got %q", result.Method)
+	}
+	if len(aptExecutor.ExecutedCommands) != 0 {
+		t.Errorf("Expected apt to be left alone, got %v", aptExecutor.ExecutedCommands)
+	}
+	if methods, _ := state.LoadMethods(stateDir); methods.Method("bat") != "" {
+		t.Errorf("Expected uninstalled tool to be forgotten, got %q", methods.Method("bat"))
+	}
+}
+
+func TestOrchestrator_ShouldSkipUnavailableInstallMethods(t *testing.T) {
+	// Test that methods the environment rejects or the tool does not configure are never run
+	mockExecutor := &MockCommandExecutor{}
+	orchestrator := &InstallationOrchestrator{
+		APTInstaller:      &APTInstaller{CommandExecutor: mockExecutor},
+		ScriptInstaller:   &ScriptInstaller{CommandExecutor: mockExecutor},
+		DownloadInstaller: &DownloadInstaller{CommandExecutor: mockExecutor, Downloader: &stubDownloader{}},
+		Arch:              "amd64",
+		MethodAvailable:   func(method string) bool { return method != "apt" },
+	}
+	tool := config.ToolConfig{
+		DisplayName:    "Bat",
+		BinaryName:     "bat",
+		InstallMethods: []string{"apt", "download", "script"},
+		PackageName:    "bat",
+		InstallScript:  "install_scripts/bat.sh",
+	}
+
+	result := orchestrator.installTool(tool)
+
+	if result.Status != StatusInstalled || result.Method != "script" {
+		t.Fatalf("Expected script to install the tool, got %q: %v", result.Method, result.Error)
+	}
+	if len(mockExecutor.ExecutedCommands) != 1 || mockExecutor.ExecutedCommands[0] != "bash install_scripts/bat.sh" {
+		t.Errorf("Expected only the script to run, got %v", mockExecutor.ExecutedCommands)
+	}
+}
+
+func TestOrchestrator_ShouldReportChainWithoutApplicableMethodAsUnsupported(t *testing.T) {
+	// Test that a chain whose methods are all skipped is unsupported rather than failed
+	orchestrator := &InstallationOrchestrator{
+		APTInstaller:      &APTInstaller{CommandExecutor: &MockCommandExecutor{}, UserMode: true},
+		DownloadInstaller: &DownloadInstaller{CommandExecutor: &MockCommandExecutor{}},
+		Arch:              "arm64",
+	}
+	tool := config.ToolConfig{
+		DisplayName:    "Bat",
+		BinaryName:     "bat",
+		InstallMethods: []string{"apt", "download"},
+		PackageName:    "bat",
+		DownloadURLs:   map[string]string{"amd64": "https://example.com/bat"},
+	}
+
+	result := orchestrator.installTool(tool)
+
+	if result.Status != StatusSkipped {
+		t.Errorf("Expected tool to be unsupported, got: %v", result.Error)
+	}
+	if !errors.Is(result.Error, ErrUnavailableInUserMode) || !errors.Is(result.Error, config.ErrUnsupportedArchitecture) {
+		t.Errorf("Expected the reason of every skipped method, got: %v", result.Error)
+	}
+}
+
+func TestOrchestrator_ShouldReportAlreadyPresentToolsWithoutInstalling(t *testing.T) {
+	// Test that detected tools are not installed again and installed tools report their version
+	mockExecutor := &MockCommandExecutor{}
+	orchestrator := &InstallationOrchestrator{
+		APTInstaller: &APTInstaller{CommandExecutor: mockExecutor},
+		Detect: func(tool config.ToolConfig) (bool, string) {
+			if tool.BinaryName == "git" {
+				return true, "git version 2.43.0"
+			}
+			return len(mockExecutor.ExecutedCommands) > 0, "bat 0.24.0"
+		},
+	}
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{{Category: "utilities", Tools: []string{"git", "bat"}}},
+	}
+	tools := map[string]config.ToolConfig{
+		"git": {DisplayName: "Git", BinaryName: "git", InstallMethod: "apt", PackageName: "git"},
+		"bat": {DisplayName: "Bat", BinaryName: "bat", InstallMethod: "apt", PackageName: "bat"},
+	}
+
+	results := orchestrator.ExecuteInstallations(selections, tools)
+
+	if results["git"].Status != StatusAlreadyPresent || results["git"].Version != "git version 2.43.0" {
+		t.Errorf("Expected git to be already present, got %s %q", results["git"].Status, results["git"].Version)
+	}
+	if results["bat"].Status != StatusInstalled || results["bat"].Version != "bat 0.24.0" {
+		t.Errorf("Expected bat to be installed with its version, got %s %q", results["bat"].Status, results["bat"].Version)
+	}
+	if results["bat"].Method != "apt" || results["bat"].FinishedAt.Before(results["bat"].StartedAt) {
+		t.Errorf("Expected method and timestamps to be recorded, got %+v", results["bat"])
+	}
+	if names := InOrder(results); len(names) != 2 || names[0] != "git" || names[1] != "bat" {
+		t.Errorf("Expected results in install order, got %v", names)
+	}
+}
+
+func TestOrchestrator_ShouldCancelRemainingToolsWhenInterrupted(t *testing.T) {
+	// Test that the tool failing during an interrupt and all later tools are cancelled, not failed
+	interrupted := false
+	executor := &MockCommandExecutor{ShouldFail: true, FailureError: errors.New("signal: interrupt")}
+	progress := &stubProgress{done: map[string]bool{}}
+	orchestrator := &InstallationOrchestrator{
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: executor},
+		Progress:        progress,
+		Retries:         3,
+		Sleep:           func(time.Duration) { t.Errorf("Expected no retry after an interrupt") },
+		Cancelled: func() bool {
+			if len(executor.ExecutedCommands) > 0 {
+				interrupted = true
+			}
+			return interrupted
+		},
+	}
+	selections := tui.Selections{
+		CategoryAndTools: []tui.CategoryAndTools{{Category: "development", Tools: []string{"first", "second"}}},
+	}
+	tools := map[string]config.ToolConfig{
+		"first":  {DisplayName: "First", InstallMethod: "script", InstallScript: "first.sh"},
+		"second": {DisplayName: "Second", InstallMethod: "script", InstallScript: "second.sh"},
+	}
+
+	results := orchestrator.ExecuteInstallations(selections, tools)
+
+	for _, name := range []string{"first", "second"} {
+		if results[name].Status != StatusCancelled || !errors.Is(results[name].Error, ErrCancelled) {
+			t.Errorf("Expected %s to be cancelled, got %s: %v", name, results[name].Status, results[name].Error)
+		}
+	}
+	if len(executor.ExecutedCommands) != 1 {
+		t.Errorf("Expected only the first tool to run, got %v", executor.ExecutedCommands)
+	}
+	if len(progress.marked) != 0 {
+		t.Errorf("Expected cancelled tools to stay unfinished for --resume, got %v", progress.marked)
+	}
+}
+
+func TestOrchestrator_ShouldKeepOutputExcerptOfEachTool(t *testing.T) {
+	// Test that each result holds the tail of its own tool's output
+	output := &OutputTail{Limit: 8}
+	orchestrator := &InstallationOrchestrator{
+		ScriptInstaller: &ScriptInstaller{CommandExecutor: &RealCommandExecutor{Capture: output}},
+		Output:          output,
+	}
+	tool := config.ToolConfig{DisplayName: "Tool", InstallMethod: "script", InstallScript: writeScript(t, "echo 0123456789abcdef")}
+
+	result := orchestrator.installTool(tool)
+	orchestrator.installTool(tool)
+
+	if result.Output != "9abcdef\n" {
+		t.Errorf("Expected the last 8 bytes of output, got %q", result.Output)
+	}
+}
+
+func TestOrchestrator_ShouldRunPostInstallCommandsAfterInstall(t *testing.T) {
+	// Test that post_install commands run in order once the tool is installed and fail the tool when they fail
+	aptExecutor := &MockCommandExecutor{}
+	postExecutor := &MockCommandExecutor{}
+	orchestrator := &InstallationOrchestrator{
+		APTInstaller:    &APTInstaller{CommandExecutor: aptExecutor},
+		CommandExecutor: postExecutor,
+	}
+	tool := config.ToolConfig{
+		DisplayName:   "Docker Engine",
+		BinaryName:    "docker",
+		InstallMethod: "apt",
+		PackageName:   "docker-ce",
+		PostInstall:   []string{`sudo usermod -aG docker "$USER"`, "sudo systemctl enable docker"},
+	}
+
+	result := orchestrator.installTool(tool)
+
+	if result.Status != StatusInstalled {
+		t.Fatalf("Expected docker to be installed, got: %v", result.Error)
+	}
+	if len(postExecutor.ExecutedCommands) != 2 || postExecutor.ExecutedCommands[1] != "sudo systemctl enable docker" {
+		t.Errorf("Expected post-install commands in order, got %v", postExecutor.ExecutedCommands)
+	}
+
+	postExecutor.ExecutedCommands, postExecutor.ShouldFail = nil, true
+	postExecutor.FailureError = errors.New("no such group")
+	result = orchestrator.installTool(tool)
+
+	if result.Status != StatusFailed || len(postExecutor.ExecutedCommands) != 1 {
+		t.Errorf("Expected failed post-install to stop and fail the tool, got %s after %v", result.Status, postExecutor.ExecutedCommands)
 	}
 }
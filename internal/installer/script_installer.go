@@ -0,0 +1,298 @@
+package installer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/petersenjoern/devenv/internal/config"
+)
+
+const (
+	scriptInstallCmd = "bash %s"
+	scriptVerbCmd    = "bash %s %s"
+
+	// scriptVerbsHeader declares the verbs a script understands, e.g.
+	// "# devenv-verbs: check install upgrade uninstall version". It must
+	// appear within the first scriptHeaderLines lines.
+	scriptVerbsHeader = "# devenv-verbs:"
+	scriptHeaderLines = 20
+
+	// checkNotInstalledCode is the exit code of the check verb for tools
+	// that are not installed; other non-zero codes are errors.
+	checkNotInstalledCode = 1
+
+	libraryEnvVar = "DEVENV_LIB"
+	bashEnvVar    = "BASH_ENV"
+	logFileEnvVar = "DEVENV_LOG_FILE"
+)
+
+// Levels of the records scripts log through the helper library.
+const (
+	ScriptLogDebug = "debug"
+	ScriptLogInfo  = "info"
+	ScriptLogWarn  = "warn"
+	ScriptLogError = "error"
+)
+
+// ScriptLogEntry is a record an install script logged through the helper
+// library.
+type ScriptLogEntry struct {
+	Level   string
+	Message string
+}
+
+// Lifecycle verbs an install script can implement.
+const (
+	VerbCheck     = "check"
+	VerbInstall   = "install"
+	VerbUpgrade   = "upgrade"
+	VerbUninstall = "uninstall"
+	VerbVersion   = "version"
+)
+
+// ErrVerbUnsupported marks scripts that do not implement a lifecycle verb,
+// including legacy scripts that declare none.
+var ErrVerbUnsupported = errors.New("verb not supported by install script")
+
+func (s *ScriptInstaller) Install(tool config.ToolConfig) error {
+	scriptPath, verbs, err := s.resolve(tool)
+	if err != nil {
+		return err
+	}
+
+	command := fmt.Sprintf(scriptInstallCmd, shellQuote(scriptPath))
+	if verbs[VerbInstall] {
+		command = fmt.Sprintf(scriptVerbCmd, shellQuote(scriptPath), VerbInstall)
+	}
+
+	if err := s.execute(tool, command); err != nil {
+		return fmt.Errorf("failed to execute install script %s: %w", tool.InstallScript, err)
+	}
+
+	return nil
+}
+
+// Upgrade runs the script's upgrade verb. Scripts without one are installed
+// again, which is how legacy scripts pick up new releases.
+func (s *ScriptInstaller) Upgrade(tool config.ToolConfig) error {
+	scriptPath, verbs, err := s.resolve(tool)
+	if err != nil {
+		return err
+	}
+	if !verbs[VerbUpgrade] {
+		return s.Install(tool)
+	}
+
+	if err := s.execute(tool, fmt.Sprintf(scriptVerbCmd, shellQuote(scriptPath), VerbUpgrade)); err != nil {
+		return fmt.Errorf("failed to upgrade with %s: %w", tool.InstallScript, err)
+	}
+	return nil
+}
+
+// Uninstall runs the script's uninstall verb.
+func (s *ScriptInstaller) Uninstall(tool config.ToolConfig) error {
+	scriptPath, verbs, err := s.resolve(tool)
+	if err != nil {
+		return err
+	}
+	if !verbs[VerbUninstall] {
+		return fmt.Errorf("%s: %w", tool.InstallScript, ErrUninstallUnsupported)
+	}
+
+	if err := s.execute(tool, fmt.Sprintf(scriptVerbCmd, shellQuote(scriptPath), VerbUninstall)); err != nil {
+		return fmt.Errorf("failed to uninstall with %s: %w", tool.InstallScript, err)
+	}
+	return nil
+}
+
+// Check asks the script whether the tool is installed. The check verb
+// exits 0 when it is and 1 when it is not.
+func (s *ScriptInstaller) Check(tool config.ToolConfig) (bool, error) {
+	scriptPath, verbs, err := s.resolve(tool)
+	if err != nil {
+		return false, err
+	}
+	if !verbs[VerbCheck] {
+		return false, fmt.Errorf("%s %s: %w", tool.InstallScript, VerbCheck, ErrVerbUnsupported)
+	}
+
+	env, err := s.env()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.probe().Output(envPrefix(env) + fmt.Sprintf(scriptVerbCmd, shellQuote(scriptPath), VerbCheck))
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == checkNotInstalledCode {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", tool.DisplayName, err)
+	}
+	return true, nil
+}
+
+// Version returns the first line the script's version verb prints.
+func (s *ScriptInstaller) Version(tool config.ToolConfig) (string, error) {
+	scriptPath, verbs, err := s.resolve(tool)
+	if err != nil {
+		return "", err
+	}
+	if !verbs[VerbVersion] {
+		return "", fmt.Errorf("%s %s: %w", tool.InstallScript, VerbVersion, ErrVerbUnsupported)
+	}
+
+	env, err := s.env()
+	if err != nil {
+		return "", err
+	}
+
+	output, err := s.probe().Output(envPrefix(env) + fmt.Sprintf(scriptVerbCmd, shellQuote(scriptPath), VerbVersion))
+	if err != nil {
+		return "", fmt.Errorf("failed to get version of %s: %w", tool.DisplayName, err)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("version verb of %s printed nothing", tool.InstallScript)
+}
+
+// execute runs a script command. With the helper library, the script's log
+// is passed to the logger and the last error explains a failure.
+func (s *ScriptInstaller) execute(tool config.ToolConfig, command string) error {
+	env, err := s.env()
+	if err != nil {
+		return err
+	}
+	if env[libraryEnvVar] == "" {
+		return s.CommandExecutor.Execute(envPrefix(env) + command)
+	}
+
+	logFile, err := os.CreateTemp("", "devenv-script-*.log")
+	if err != nil {
+		return fmt.Errorf("failed to create script log: %w", err)
+	}
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	env[logFileEnvVar] = logFile.Name()
+	runErr := s.CommandExecutor.Execute(envPrefix(env) + command)
+
+	var lastError string
+	if data, err := os.ReadFile(logFile.Name()); err == nil {
+		logger := loggerOr(s.Logger).With("tool", tool.DisplayName, "script", tool.InstallScript)
+		for _, entry := range ParseScriptLog(string(data)) {
+			switch entry.Level {
+			case ScriptLogDebug:
+				logger.Debug(entry.Message)
+			case ScriptLogInfo:
+				logger.Info(entry.Message)
+			case ScriptLogWarn:
+				logger.Warn(entry.Message)
+			case ScriptLogError:
+				logger.Error(entry.Message)
+				lastError = entry.Message
+			}
+		}
+	}
+
+	if runErr != nil && lastError != "" {
+		return fmt.Errorf("%s: %w", lastError, runErr)
+	}
+	return runErr
+}
+
+// env returns the script environment, pointing DEVENV_LIB and BASH_ENV at
+// the helper library when one is configured so bash sources it first.
+func (s *ScriptInstaller) env() (map[string]string, error) {
+	env := make(map[string]string, len(s.Env)+3)
+	for key, value := range s.Env {
+		env[key] = value
+	}
+	if s.Library == "" {
+		return env, nil
+	}
+
+	library := s.Library
+	if s.Assets != nil {
+		resolved, err := s.Assets.Resolve(s.Library)
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate script library %s: %w", s.Library, err)
+		}
+		library = resolved
+	}
+
+	env[libraryEnvVar] = library
+	env[bashEnvVar] = library
+	return env, nil
+}
+
+// ParseScriptLog parses the "<level><TAB><message>" records the helper
+// library writes to DEVENV_LOG_FILE. Malformed lines are ignored.
+func ParseScriptLog(data string) []ScriptLogEntry {
+	var entries []ScriptLogEntry
+	for _, line := range strings.Split(data, "\n") {
+		level, message, found := strings.Cut(line, "\t")
+		if !found || level == "" {
+			continue
+		}
+		entries = append(entries, ScriptLogEntry{Level: level, Message: message})
+	}
+	return entries
+}
+
+func (s *ScriptInstaller) probe() OutputExecutor {
+	if s.Probe != nil {
+		return s.Probe
+	}
+	return &RealCommandExecutor{}
+}
+
+// resolve locates the tool's script and reads the verbs it declares.
+func (s *ScriptInstaller) resolve(tool config.ToolConfig) (string, map[string]bool, error) {
+	if tool.InstallScript == "" {
+		return "", nil, fmt.Errorf("install script path is required for script installation method")
+	}
+
+	scriptPath := tool.InstallScript
+	if s.Assets != nil {
+		resolved, err := s.Assets.Resolve(tool.InstallScript)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to locate install script %s: %w", tool.InstallScript, err)
+		}
+		scriptPath = resolved
+	}
+
+	return scriptPath, scriptVerbs(scriptPath), nil
+}
+
+// scriptVerbs returns the verbs declared in the script's header. Scripts
+// that cannot be read here are treated as legacy scripts; running them
+// reports the actual error.
+func scriptVerbs(scriptPath string) map[string]bool {
+	verbs := make(map[string]bool)
+
+	file, err := os.Open(scriptPath)
+	if err != nil {
+		return verbs
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 0; line < scriptHeaderLines && scanner.Scan(); line++ {
+		if declared, found := strings.CutPrefix(strings.TrimSpace(scanner.Text()), scriptVerbsHeader); found {
+			for _, verb := range strings.Fields(declared) {
+				verbs[verb] = true
+			}
+			break
+		}
+	}
+	return verbs
+}
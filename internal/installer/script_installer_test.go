@@ -1,7 +1,12 @@
 package installer
 
 import (
+	"bytes"
 	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/petersenjoern/devenv/internal/config"
@@ -115,3 +120,312 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+type stubAssetResolver struct {
+	resolved map[string]string
+}
+
+func (s *stubAssetResolver) Resolve(name string) (string, error) {
+	if path, ok := s.resolved[name]; ok {
+		return path, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestScriptInstaller_ShouldRunResolvedEmbeddedScript(t *testing.T) {
+	// Test that ScriptInstaller executes the script path returned by the asset resolver
+	mockExecutor := &MockCommandExecutor{}
+	installer := &ScriptInstaller{
+		CommandExecutor: mockExecutor,
+		Assets: &stubAssetResolver{resolved: map[string]string{
+			"install_scripts/fzf.sh": "/tmp/devenv-assets-1/install_scripts/fzf.sh",
+		}},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "FZF Fuzzy Finder",
+		BinaryName:    "fzf",
+		InstallMethod: "script",
+		InstallScript: "install_scripts/fzf.sh",
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expectedCmd := "bash /tmp/devenv-assets-1/install_scripts/fzf.sh"
+	if len(mockExecutor.ExecutedCommands) != 1 || mockExecutor.ExecutedCommands[0] != expectedCmd {
+		t.Errorf("Expected command '%s', got %v", expectedCmd, mockExecutor.ExecutedCommands)
+	}
+}
+
+func TestScriptInstaller_ShouldFailWhenScriptCannotBeResolved(t *testing.T) {
+	// Test that ScriptInstaller reports missing scripts without executing anything
+	mockExecutor := &MockCommandExecutor{}
+	installer := &ScriptInstaller{
+		CommandExecutor: mockExecutor,
+		Assets:          &stubAssetResolver{},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Missing Script Tool",
+		BinaryName:    "missing",
+		InstallMethod: "script",
+		InstallScript: "install_scripts/missing.sh",
+	}
+
+	if err := installer.Install(tool); err == nil {
+		t.Errorf("Expected error when script cannot be resolved")
+	}
+
+	if len(mockExecutor.ExecutedCommands) != 0 {
+		t.Errorf("Expected no commands to be executed, got %v", mockExecutor.ExecutedCommands)
+	}
+}
+
+func TestScriptInstaller_ShouldPassEnvironmentToScript(t *testing.T) {
+	// Test that configured environment variables prefix the script command
+	mockExecutor := &MockCommandExecutor{}
+	installer := &ScriptInstaller{
+		CommandExecutor: mockExecutor,
+		Env:             map[string]string{"DEVENV_BUNDLE_DIR": "/media/usb/devenv bundle"},
+	}
+
+	tool := config.ToolConfig{
+		DisplayName:   "Lazygit Terminal UI",
+		BinaryName:    "lazygit",
+		InstallMethod: "script",
+		InstallScript: "install_scripts/lazygit.sh",
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expectedCmd := "DEVENV_BUNDLE_DIR='/media/usb/devenv bundle' bash install_scripts/lazygit.sh"
+	if len(mockExecutor.ExecutedCommands) != 1 || mockExecutor.ExecutedCommands[0] != expectedCmd {
+		t.Errorf("Expected command '%s', got %v", expectedCmd, mockExecutor.ExecutedCommands)
+	}
+}
+
+// verbScript is an install script implementing the lifecycle verbs; check
+// exits with $CHECK_CODE and version prints $VERSION.
+const verbScript = `#!/bin/bash
+# DevEnv - Test Script
+# devenv-verbs: check install upgrade uninstall version
+case "$1" in
+    check) exit "${CHECK_CODE:-0}" ;;
+    version) echo ""; echo "tool $VERSION"; echo "extra" ;;
+esac
+`
+
+func writeScript(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tool.sh")
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestScriptInstaller_ShouldPassVerbsToScriptsDeclaringThem(t *testing.T) {
+	// Test that scripts with a verbs header are called with the lifecycle verb
+	mockExecutor := &MockCommandExecutor{}
+	installer := &ScriptInstaller{CommandExecutor: mockExecutor}
+	script := writeScript(t, verbScript)
+	tool := config.ToolConfig{DisplayName: "Tool", InstallMethod: "script", InstallScript: script}
+
+	for _, run := range []func(config.ToolConfig) error{installer.Install, installer.Upgrade, installer.Uninstall} {
+		if err := run(tool); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	expected := []string{"bash " + script + " install", "bash " + script + " upgrade", "bash " + script + " uninstall"}
+	if strings.Join(mockExecutor.ExecutedCommands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected commands %v, got %v", expected, mockExecutor.ExecutedCommands)
+	}
+}
+
+func TestScriptInstaller_ShouldFallBackForLegacyScripts(t *testing.T) {
+	// Test that scripts without verbs are re-run to upgrade and cannot be uninstalled or checked
+	mockExecutor := &MockCommandExecutor{}
+	installer := &ScriptInstaller{CommandExecutor: mockExecutor}
+	script := writeScript(t, "#!/bin/bash\necho installing\n")
+	tool := config.ToolConfig{DisplayName: "Tool", InstallMethod: "script", InstallScript: script}
+
+	if err := installer.Upgrade(tool); err != nil {
+		t.Fatalf("Expected upgrade to re-run the script, got: %v", err)
+	}
+	if len(mockExecutor.ExecutedCommands) != 1 || mockExecutor.ExecutedCommands[0] != "bash "+script {
+		t.Errorf("Expected legacy install command, got %v", mockExecutor.ExecutedCommands)
+	}
+
+	if err := installer.Uninstall(tool); !errors.Is(err, ErrUninstallUnsupported) {
+		t.Errorf("Expected ErrUninstallUnsupported, got: %v", err)
+	}
+	if _, err := installer.Check(tool); !errors.Is(err, ErrVerbUnsupported) {
+		t.Errorf("Expected ErrVerbUnsupported from check, got: %v", err)
+	}
+	if _, err := installer.Version(tool); !errors.Is(err, ErrVerbUnsupported) {
+		t.Errorf("Expected ErrVerbUnsupported from version, got: %v", err)
+	}
+}
+
+func TestScriptInstaller_ShouldCheckAndReadVersionFromScript(t *testing.T) {
+	// Test that check maps exit codes to installed, not installed and error, and version reads the first line
+	script := writeScript(t, verbScript)
+	tool := config.ToolConfig{DisplayName: "Tool", InstallMethod: "script", InstallScript: script}
+
+	for code, want := range map[string]bool{"0": true, "1": false} {
+		installer := &ScriptInstaller{Env: map[string]string{"CHECK_CODE": code}}
+		installed, err := installer.Check(tool)
+		if err != nil || installed != want {
+			t.Errorf("Expected exit code %s to report installed=%v, got %v (%v)", code, want, installed, err)
+		}
+	}
+
+	installer := &ScriptInstaller{Env: map[string]string{"CHECK_CODE": "3"}}
+	if _, err := installer.Check(tool); err == nil {
+		t.Errorf("Expected other exit codes to be errors")
+	}
+
+	installer = &ScriptInstaller{Env: map[string]string{"VERSION": "1.4.2"}}
+	version, err := installer.Version(tool)
+	if err != nil || version != "tool 1.4.2" {
+		t.Errorf("Expected version 'tool 1.4.2', got %q (%v)", version, err)
+	}
+}
+
+const scriptLibrary = "../../install_scripts/lib/devenv.sh"
+
+func TestScriptInstaller_ShouldSourceHelperLibrary(t *testing.T) {
+	// Test that the helper library is sourced through BASH_ENV and exposed as DEVENV_LIB
+	script := writeScript(t, `#!/bin/bash
+set -e
+[ "$(devenv_arch)" = "aarch64" ] || devenv_die "unexpected arch $(devenv_arch)"
+[ "$(devenv_arch x86_64 arm64)" = "arm64" ] || devenv_die "arch override ignored"
+[ -f "$DEVENV_LIB" ] || devenv_die "DEVENV_LIB not set"
+devenv_verify_binary sh -c 'echo sh 1.0'
+`)
+	installer := &ScriptInstaller{
+		CommandExecutor: &RealCommandExecutor{},
+		Env:             map[string]string{"DEVENV_ARCH": "arm64"},
+		Library:         scriptLibrary,
+	}
+
+	if err := installer.Install(config.ToolConfig{DisplayName: "Tool", InstallScript: script}); err != nil {
+		t.Fatalf("Expected script using the helpers to succeed, got: %v", err)
+	}
+}
+
+func TestScriptInstaller_ShouldReportLoggedErrorOnFailure(t *testing.T) {
+	// Test that the last error a script logs through the library explains the failure
+	script := writeScript(t, `#!/bin/bash
+set -e
+devenv_warn "using fallback mirror"
+devenv_download "file:///nonexistent/tool.tar.gz" "$(mktemp -d)/tool.tar.gz"
+`)
+	var logs bytes.Buffer
+	installer := &ScriptInstaller{
+		CommandExecutor: &RealCommandExecutor{},
+		Library:         scriptLibrary,
+		Logger:          slog.New(slog.NewTextHandler(&logs, nil)),
+	}
+
+	err := installer.Install(config.ToolConfig{DisplayName: "Tool", InstallScript: script})
+
+	if err == nil || !strings.Contains(err.Error(), "failed to download file:///nonexistent/tool.tar.gz") {
+		t.Errorf("Expected logged download error in failure, got: %v", err)
+	}
+	if !strings.Contains(logs.String(), `level=WARN msg="using fallback mirror" tool=Tool`) {
+		t.Errorf("Expected script warning to be passed to the logger, got: %s", logs.String())
+	}
+}
+
+func TestParseScriptLog_ShouldParseLevelsAndSkipMalformedLines(t *testing.T) {
+	// Test that log records are split into level and message
+	entries := ParseScriptLog("info\tInstalling tool\nnot a record\nerror\tchecksum mismatch for tool.tar.gz\n")
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", entries)
+	}
+	if entries[1].Level != ScriptLogError || entries[1].Message != "checksum mismatch for tool.tar.gz" {
+		t.Errorf("Expected error entry, got %+v", entries[1])
+	}
+}
+
+func TestInstallScripts_ShouldSourceHelperLibraryThemselves(t *testing.T) {
+	// Test that shipped scripts using the helpers also work when run by hand, without BASH_ENV
+	scripts, err := filepath.Glob("../../install_scripts/*.sh")
+	if err != nil || len(scripts) == 0 {
+		t.Fatalf("Expected install scripts, got %v (%v)", scripts, err)
+	}
+
+	for _, script := range scripts {
+		content, err := os.ReadFile(script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(content), "devenv_") && !strings.Contains(string(content), `. "${DEVENV_LIB:-$(dirname "$0")/lib/devenv.sh}"`) {
+			t.Errorf("Expected %s to source the helper library", script)
+		}
+	}
+}
+
+func TestInstallScripts_ShouldGateAptOnInstallPrefix(t *testing.T) {
+	// Test that shipped scripts only call apt for system-wide installs, never in --user mode
+	scripts, err := filepath.Glob("../../install_scripts/*.sh")
+	if err != nil || len(scripts) == 0 {
+		t.Fatalf("Expected install scripts, got %v (%v)", scripts, err)
+	}
+
+	for _, script := range scripts {
+		content, err := os.ReadFile(script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(content), "sudo apt") && !strings.Contains(string(content), "devenv_prefix") {
+			t.Errorf("Expected %s to check devenv_prefix before using apt", script)
+		}
+	}
+}
+
+func TestScriptInstaller_ShouldRunScriptsFromConfigDirectoryWithSpace(t *testing.T) {
+	// Test that scripts resolved under a config directory with a space still run
+	dir := filepath.Join(t.TempDir(), "dev env")
+	marker := filepath.Join(t.TempDir(), "installed")
+	files := map[string]string{
+		"config.yaml": "categories:\n  tools:\n    tool:\n      install_method: script\n      install_script: install_scripts/tool.sh\n",
+		"install_scripts/tool.sh": "#!/bin/bash\n# devenv-verbs: check install\n" +
+			"case \"$1\" in\n    install) touch \"$MARKER\" ;;\n    check) [ -f \"$MARKER\" ] || exit 1 ;;\nesac\n",
+		"install_scripts/lib/devenv.sh": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := config.LoadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("Expected config to load, got: %v", err)
+	}
+	tool := cfg.Categories["tools"]["tool"]
+	installer := &ScriptInstaller{
+		CommandExecutor: &RealCommandExecutor{},
+		Env:             map[string]string{"MARKER": marker},
+		Library:         cfg.ScriptLibrary,
+	}
+
+	if err := installer.Install(tool); err != nil {
+		t.Fatalf("Expected install to run the script, got: %v", err)
+	}
+	if installed, err := installer.Check(tool); err != nil || !installed {
+		t.Errorf("Expected check to find the tool installed, got %v (%v)", installed, err)
+	}
+}
@@ -0,0 +1,407 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/download"
+)
+
+const (
+	stepMkdirCmd   = "%smkdir -p %s"
+	stepCopyCmd    = "%sinstall -D -m %s %s %s"
+	stepSymlinkCmd = "%smkdir -p %s && %sln -sfn %s %s"
+	stepRemoveCmd  = "%srm -f %s"
+	stepRunCmd     = "cd %s && %ssh -c %s"
+
+	defaultStepMode   = "0755"
+	defaultStepPrefix = "/usr/local"
+	prefixPlaceholder = "{prefix}"
+	accessWriteOK     = 0x2
+
+	stepFailedMsg = "step %d (%s) failed: %w"
+)
+
+// StepStatus is the outcome of a single pipeline step.
+type StepStatus string
+
+const (
+	StepDone    StepStatus = "done"
+	StepFailed  StepStatus = "failed"
+	StepSkipped StepStatus = "skipped"
+	// StepPlanned marks steps described by a dry run.
+	StepPlanned StepStatus = "planned"
+)
+
+// StepResult reports what a step did, or would do in a dry run.
+type StepResult struct {
+	Step        config.Step
+	Description string
+	Status      StepStatus
+	Error       error
+}
+
+// StepInstaller runs the declarative steps of the steps install method.
+// Files are placed with sudo only where the current user cannot write.
+type StepInstaller struct {
+	CommandExecutor CommandExecutor
+	Downloader      download.Downloader
+	// APT installs package steps; its user mode and offline packages apply.
+	APT *APTInstaller
+	// Arch expands {arch}; defaults to the running binary's.
+	Arch string
+	// Prefix expands {prefix}; defaults to /usr/local.
+	Prefix string
+	// Env is exported to run and verify commands.
+	Env map[string]string
+	// Journal, when set, records copied files and symlinks for rollback.
+	Journal ChangeRecorder
+	// HomeDir expands ~ in paths; defaults to the user's home.
+	HomeDir string
+	// DryRun describes every step without running it.
+	DryRun bool
+	// Writable reports whether path can be created without sudo; defaults
+	// to checking its closest existing ancestor.
+	Writable func(path string) bool
+}
+
+func NewStepInstaller() *StepInstaller {
+	return &StepInstaller{
+		CommandExecutor: &RealCommandExecutor{},
+		Downloader:      download.NewHTTPDownloader(),
+	}
+}
+
+// stepAction is a step with its values expanded, ready to run.
+type stepAction struct {
+	description string
+	run         func() error
+}
+
+func (s *StepInstaller) Install(tool config.ToolConfig) error {
+	_, err := s.Run(tool)
+	return err
+}
+
+// Run executes the tool's steps in order and stops at the first failure.
+// Every step gets a result; steps after a failure are reported as skipped.
+func (s *StepInstaller) Run(tool config.ToolConfig) ([]StepResult, error) {
+	if len(tool.Steps) == 0 {
+		return nil, fmt.Errorf("steps are required for steps installation method")
+	}
+
+	var workDir string
+	if !s.DryRun {
+		dir, err := os.MkdirTemp("", "devenv-steps-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create work directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+		workDir = dir
+	}
+
+	return s.runAll(tool, tool.Steps, workDir, s.installAction)
+}
+
+func (s *StepInstaller) Uninstall(tool config.ToolConfig) error {
+	_, err := s.Revert(tool)
+	return err
+}
+
+// Revert undoes the tool's steps in reverse order: copied files and
+// symlinks are removed, packages uninstalled and run steps reverted with
+// their undo command. Downloads, extractions, directories and verifications
+// have nothing to undo and are reported as skipped.
+func (s *StepInstaller) Revert(tool config.ToolConfig) ([]StepResult, error) {
+	reversed := make([]config.Step, len(tool.Steps))
+	for i, step := range tool.Steps {
+		reversed[len(tool.Steps)-1-i] = step
+	}
+
+	workDir := os.TempDir()
+	return s.runAll(tool, reversed, workDir, s.undoAction)
+}
+
+func (s *StepInstaller) runAll(tool config.ToolConfig, steps []config.Step, workDir string, prepare func(config.ToolConfig, config.Step, string) (*stepAction, error)) ([]StepResult, error) {
+	results := make([]StepResult, len(steps))
+	for i, step := range steps {
+		results[i] = StepResult{Step: step, Description: step.Action, Status: StepSkipped}
+	}
+
+	for i, step := range steps {
+		action, err := prepare(tool, step, workDir)
+		if err == nil && action == nil {
+			continue
+		}
+		if err == nil {
+			results[i].Description = action.description
+			if !s.DryRun {
+				err = action.run()
+			}
+		}
+
+		if err != nil {
+			results[i].Status, results[i].Error = StepFailed, err
+			return results, fmt.Errorf(stepFailedMsg, i+1, results[i].Description, err)
+		}
+
+		results[i].Status = StepDone
+		if s.DryRun {
+			results[i].Status = StepPlanned
+		}
+	}
+
+	return results, nil
+}
+
+func (s *StepInstaller) installAction(tool config.ToolConfig, step config.Step, workDir string) (*stepAction, error) {
+	switch step.Action {
+	case config.StepPackage:
+		packages, err := s.expand(tool, step.Package)
+		if err != nil {
+			return nil, err
+		}
+		return &stepAction{
+			description: "install package " + packages,
+			run:         func() error { return s.installPackages(tool, packages) },
+		}, nil
+
+	case config.StepDownload:
+		url, err := s.expand(tool, step.URL)
+		if err != nil {
+			return nil, err
+		}
+		dest := step.Dest
+		if dest == "" {
+			dest = path.Base(url)
+		}
+		if dest, err = s.path(tool, dest, workDir); err != nil {
+			return nil, err
+		}
+		return &stepAction{
+			description: fmt.Sprintf("download %s to %s", url, dest),
+			run:         func() error { return s.Downloader.Download(url, step.SHA256, dest) },
+		}, nil
+
+	case config.StepExtract:
+		src, dest, err := s.paths(tool, step.Src, step.Dest, workDir)
+		if err != nil {
+			return nil, err
+		}
+		description := fmt.Sprintf("extract %s to %s", src, dest)
+		if len(step.Files) > 0 {
+			description += " (" + strings.Join(step.Files, ", ") + ")"
+		}
+		return &stepAction{
+			description: description,
+			run:         func() error { return download.Extract(src, dest, step.Files) },
+		}, nil
+
+	case config.StepCopy:
+		src, dest, err := s.paths(tool, step.Src, step.Dest, workDir)
+		if err != nil {
+			return nil, err
+		}
+		mode := step.Mode
+		if mode == "" {
+			mode = defaultStepMode
+		}
+		sudo := s.sudo(dest)
+		return &stepAction{
+			description: s.describe(fmt.Sprintf("copy %s to %s", src, dest), sudo),
+			run: func() error {
+				if _, err := recordFileChange(s.Journal, tool.BinaryName, dest, sudo != ""); err != nil {
+					return err
+				}
+				return s.CommandExecutor.Execute(fmt.Sprintf(stepCopyCmd, sudo, mode, shellQuote(src), shellQuote(dest)))
+			},
+		}, nil
+
+	case config.StepSymlink:
+		target, link, err := s.paths(tool, step.Src, step.Dest, workDir)
+		if err != nil {
+			return nil, err
+		}
+		sudo := s.sudo(link)
+		return &stepAction{
+			description: s.describe(fmt.Sprintf("link %s to %s", link, target), sudo),
+			run: func() error {
+				if _, err := recordFileChange(s.Journal, tool.BinaryName, link, sudo != ""); err != nil {
+					return err
+				}
+				return s.CommandExecutor.Execute(fmt.Sprintf(stepSymlinkCmd, sudo, shellQuote(filepath.Dir(link)), sudo, shellQuote(target), shellQuote(link)))
+			},
+		}, nil
+
+	case config.StepMkdir:
+		dir, err := s.path(tool, step.Dest, workDir)
+		if err != nil {
+			return nil, err
+		}
+		sudo := s.sudo(dir)
+		return &stepAction{
+			description: s.describe("create directory "+dir, sudo),
+			run:         func() error { return s.CommandExecutor.Execute(fmt.Sprintf(stepMkdirCmd, sudo, shellQuote(dir))) },
+		}, nil
+
+	case config.StepRun:
+		return s.commandAction(tool, "run", step.Command, workDir, nil)
+
+	case config.StepVerify:
+		return s.commandAction(tool, "verify", step.Command, workDir, func(err error) error {
+			return fmt.Errorf("verification failed: %w", err)
+		})
+
+	default:
+		return nil, fmt.Errorf("unknown step action: %q", step.Action)
+	}
+}
+
+// undoAction returns how to revert step, or nil when there is nothing to undo.
+func (s *StepInstaller) undoAction(tool config.ToolConfig, step config.Step, workDir string) (*stepAction, error) {
+	switch step.Action {
+	case config.StepPackage:
+		packages, err := s.expand(tool, step.Package)
+		if err != nil {
+			return nil, err
+		}
+		return &stepAction{
+			description: "remove package " + packages,
+			run: func() error {
+				if s.APT == nil {
+					return fmt.Errorf("no package manager configured")
+				}
+				return s.APT.Uninstall(config.ToolConfig{DisplayName: tool.DisplayName, PackageName: packages})
+			},
+		}, nil
+
+	case config.StepCopy, config.StepSymlink:
+		dest, err := s.path(tool, step.Dest, workDir)
+		if err != nil {
+			return nil, err
+		}
+		sudo := s.sudo(dest)
+		return &stepAction{
+			description: s.describe("remove "+dest, sudo),
+			run:         func() error { return s.CommandExecutor.Execute(fmt.Sprintf(stepRemoveCmd, sudo, shellQuote(dest))) },
+		}, nil
+
+	case config.StepRun:
+		if step.Undo == "" {
+			return nil, nil
+		}
+		return s.commandAction(tool, "run", step.Undo, workDir, nil)
+
+	default:
+		return nil, nil
+	}
+}
+
+func (s *StepInstaller) commandAction(tool config.ToolConfig, verb, command, workDir string, wrap func(error) error) (*stepAction, error) {
+	if command == "" {
+		return nil, fmt.Errorf("%s step requires a command", verb)
+	}
+	command, err := s.expand(tool, command)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stepAction{
+		description: verb + ": " + command,
+		run: func() error {
+			err := s.CommandExecutor.Execute(fmt.Sprintf(stepRunCmd, shellQuote(workDir), envPrefix(s.Env), shellQuote(command)))
+			if err != nil && wrap != nil {
+				return wrap(err)
+			}
+			return err
+		},
+	}, nil
+}
+
+func (s *StepInstaller) installPackages(tool config.ToolConfig, packages string) error {
+	if s.APT == nil {
+		return fmt.Errorf("no package manager configured")
+	}
+	return s.APT.Install(config.ToolConfig{DisplayName: tool.DisplayName, PackageName: packages})
+}
+
+// expand replaces {version}, {arch} and {prefix} in value.
+func (s *StepInstaller) expand(tool config.ToolConfig, value string) (string, error) {
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = defaultStepPrefix
+	}
+	return config.ExpandStepValue(tool, strings.ReplaceAll(value, prefixPlaceholder, prefix), hostArch(s.Arch))
+}
+
+// path expands value and resolves it against the home directory or, when
+// relative, the work directory.
+func (s *StepInstaller) path(tool config.ToolConfig, value, workDir string) (string, error) {
+	if value == "" {
+		value = "."
+	}
+
+	expanded, err := s.expand(tool, value)
+	if err != nil {
+		return "", err
+	}
+	if expanded, err = expandHome(expanded, s.HomeDir); err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(expanded) {
+		return filepath.Join(workDir, expanded), nil
+	}
+	return expanded, nil
+}
+
+func (s *StepInstaller) paths(tool config.ToolConfig, src, dest, workDir string) (string, string, error) {
+	resolvedSrc, err := s.path(tool, src, workDir)
+	if err != nil {
+		return "", "", err
+	}
+	resolvedDest, err := s.path(tool, dest, workDir)
+	if err != nil {
+		return "", "", err
+	}
+	return resolvedSrc, resolvedDest, nil
+}
+
+// sudo returns the prefix needed to create path.
+func (s *StepInstaller) sudo(path string) string {
+	writable := s.Writable
+	if writable == nil {
+		writable = canCreate
+	}
+	if writable(path) {
+		return ""
+	}
+	return sudoPrefix
+}
+
+func (s *StepInstaller) describe(description, sudo string) string {
+	if sudo != "" {
+		return description + " (sudo)"
+	}
+	return description
+}
+
+// canCreate reports whether the current user may write path's closest
+// existing ancestor.
+func canCreate(path string) bool {
+	for {
+		if _, err := os.Lstat(path); err == nil {
+			return syscall.Access(path, accessWriteOK) == nil
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return false
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,210 @@
+package installer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/cache"
+	"github.com/petersenjoern/devenv/internal/config"
+)
+
+func tarGzWith(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Write([]byte(content))
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func pipelineTool() config.ToolConfig {
+	return config.ToolConfig{
+		DisplayName:   "Tool",
+		BinaryName:    "tool",
+		InstallMethod: "steps",
+		Version:       "1.2.3",
+		Steps: []config.Step{
+			{Action: config.StepDownload, URL: "https://example.com/tool_{version}_Linux_{arch}.tar.gz", Dest: "tool.tar.gz"},
+			{Action: config.StepExtract, Src: "tool.tar.gz", Files: []string{"tool"}},
+			{Action: config.StepCopy, Src: "tool", Dest: "{prefix}/bin/tool"},
+			{Action: config.StepSymlink, Src: "{prefix}/bin/tool", Dest: "{prefix}/bin/t"},
+			{Action: config.StepVerify, Command: "{prefix}/bin/t"},
+		},
+	}
+}
+
+// Test that a pipeline downloads, extracts, installs and verifies a binary
+func TestStepInstaller_ShouldRunPipeline(t *testing.T) {
+	prefix := t.TempDir()
+	downloader := &stubDownloader{content: tarGzWith(t, "tool", "#!/bin/sh\nexit 0\n")}
+	installer := &StepInstaller{
+		CommandExecutor: &RealCommandExecutor{},
+		Downloader:      downloader,
+		Arch:            "amd64",
+		Prefix:          prefix,
+	}
+
+	results, err := installer.Run(pipelineTool())
+
+	if err != nil {
+		t.Fatalf("Expected pipeline to succeed, got: %v", err)
+	}
+	if len(downloader.urls) != 1 || downloader.urls[0] != "https://example.com/tool_1.2.3_Linux_x86_64.tar.gz" {
+		t.Errorf("Expected version and arch to be expanded in URL, got %v", downloader.urls)
+	}
+	for _, result := range results {
+		if result.Status != StepDone {
+			t.Errorf("Expected step %q to be done, got %s (%v)", result.Description, result.Status, result.Error)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(prefix, "bin", "t")); err != nil || target != filepath.Join(prefix, "bin", "tool") {
+		t.Errorf("Expected symlink to installed binary, got %q (%v)", target, err)
+	}
+}
+
+// Test that a failing step stops the pipeline and later steps are reported as skipped
+func TestStepInstaller_ShouldStopAtFailedStep(t *testing.T) {
+	installer := &StepInstaller{
+		CommandExecutor: &RealCommandExecutor{},
+		Downloader:      &stubDownloader{err: errors.New("network down")},
+		Prefix:          t.TempDir(),
+	}
+
+	results, err := installer.Run(pipelineTool())
+
+	if err == nil || !strings.Contains(err.Error(), "step 1 (download") {
+		t.Fatalf("Expected error naming the failed step, got: %v", err)
+	}
+	if results[0].Status != StepFailed {
+		t.Errorf("Expected first step to fail, got %s", results[0].Status)
+	}
+	for _, result := range results[1:] {
+		if result.Status != StepSkipped {
+			t.Errorf("Expected step %q to be skipped, got %s", result.Description, result.Status)
+		}
+	}
+}
+
+// Test that a dry run describes every step without executing or downloading anything
+func TestStepInstaller_ShouldDescribeStepsInDryRun(t *testing.T) {
+	mockExecutor := &MockCommandExecutor{}
+	downloader := &stubDownloader{}
+	installer := &StepInstaller{
+		CommandExecutor: mockExecutor,
+		Downloader:      downloader,
+		Arch:            "arm64",
+		Prefix:          "/opt/devenv",
+		Writable:        func(string) bool { return false },
+		DryRun:          true,
+	}
+
+	results, err := installer.Run(pipelineTool())
+
+	if err != nil {
+		t.Fatalf("Expected dry run to succeed, got: %v", err)
+	}
+	if len(mockExecutor.ExecutedCommands) != 0 || len(downloader.urls) != 0 {
+		t.Errorf("Expected nothing to run, got commands %v and downloads %v", mockExecutor.ExecutedCommands, downloader.urls)
+	}
+	if results[2].Status != StepPlanned || results[2].Description != "copy tool to /opt/devenv/bin/tool (sudo)" {
+		t.Errorf("Expected planned copy with sudo, got %s %q", results[2].Status, results[2].Description)
+	}
+	if !strings.Contains(results[0].Description, "tool_1.2.3_Linux_aarch64.tar.gz") {
+		t.Errorf("Expected expanded download URL, got %q", results[0].Description)
+	}
+}
+
+// Test that uninstall reverts placed files, packages and run steps in reverse order
+func TestStepInstaller_ShouldRevertStepsInReverseOrder(t *testing.T) {
+	mockExecutor := &MockCommandExecutor{}
+	installer := &StepInstaller{
+		CommandExecutor: mockExecutor,
+		APT:             &APTInstaller{CommandExecutor: mockExecutor},
+		Prefix:          "/usr/local",
+		Writable:        func(string) bool { return false },
+	}
+	tool := config.ToolConfig{
+		DisplayName: "Tool",
+		Steps: []config.Step{
+			{Action: config.StepPackage, Package: "libfoo"},
+			{Action: config.StepCopy, Src: "tool", Dest: "{prefix}/bin/tool"},
+			{Action: config.StepRun, Command: "tool setup", Undo: "tool teardown"},
+			{Action: config.StepMkdir, Dest: "~/.config/tool"},
+		},
+	}
+
+	results, err := installer.Revert(tool)
+
+	if err != nil {
+		t.Fatalf("Expected revert to succeed, got: %v", err)
+	}
+	if results[0].Status != StepSkipped {
+		t.Errorf("Expected mkdir to have nothing to undo, got %s", results[0].Status)
+	}
+
+	expected := []string{"sh -c 'tool teardown'", "sudo rm -f /usr/local/bin/tool", "sudo apt remove -y libfoo"}
+	if len(mockExecutor.ExecutedCommands) != len(expected) {
+		t.Fatalf("Expected %d commands, got %v", len(expected), mockExecutor.ExecutedCommands)
+	}
+	for i, want := range expected {
+		if !strings.HasSuffix(mockExecutor.ExecutedCommands[i], want) {
+			t.Errorf("Expected command %d to end with %q, got %q", i, want, mockExecutor.ExecutedCommands[i])
+		}
+	}
+}
+
+// Test that package steps are reported as unavailable in user mode
+func TestStepInstaller_ShouldReportPackageStepsUnavailableInUserMode(t *testing.T) {
+	installer := &StepInstaller{
+		CommandExecutor: &MockCommandExecutor{},
+		APT:             &APTInstaller{CommandExecutor: &MockCommandExecutor{}, UserMode: true},
+	}
+	tool := config.ToolConfig{DisplayName: "Tool", Steps: []config.Step{{Action: config.StepPackage, Package: "libfoo"}}}
+
+	_, err := installer.Run(tool)
+
+	if !errors.Is(err, ErrUnavailableInUserMode) {
+		t.Errorf("Expected ErrUnavailableInUserMode, got: %v", err)
+	}
+}
+
+// Test that reinstalling a pinned catalog tool reads its download from the cache
+func TestStepInstaller_ShouldReuseCachedDownloadOnReinstall(t *testing.T) {
+	cfg, err := config.LoadConfig("../../config.yaml")
+	if err != nil {
+		t.Fatalf("Expected catalog to load, got: %v", err)
+	}
+	lazygit, found := config.GetTool(cfg, "utilities", "lazygit")
+	if !found {
+		t.Fatal("Expected lazygit in the catalog")
+	}
+
+	network := &stubDownloader{content: tarGzWith(t, "lazygit", "#!/bin/sh\n")}
+	cacheDir := t.TempDir()
+	for run := 0; run < 2; run++ {
+		installer := &StepInstaller{
+			CommandExecutor: &MockCommandExecutor{},
+			Downloader:      &cache.Downloader{Cache: cache.New(cacheDir), Downloader: network},
+			Arch:            "amd64",
+			Prefix:          t.TempDir(),
+		}
+		if _, err := installer.Run(lazygit); err != nil {
+			t.Fatalf("Run %d: expected install to succeed, got: %v", run, err)
+		}
+	}
+
+	if len(network.urls) != 1 {
+		t.Errorf("Expected the second install to use the cache, got %d downloads", len(network.urls))
+	}
+}
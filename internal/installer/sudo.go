@@ -0,0 +1,186 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sudoPrefix         = "sudo "
+	sudoBinary         = "sudo"
+	shimPathCmd        = "export PATH=%s:\"$PATH\"; "
+	sudoValidateArg    = "-v"
+	sudoNonInteractive = "-n"
+
+	defaultSudoRefreshInterval = time.Minute
+)
+
+// sudoShim lets scripts that call sudo run unchanged as root on systems
+// where sudo is not installed (e.g. minimal containers). Flags such as -E
+// are dropped, options with an argument are dropped together with it, and
+// the command is executed directly. Running as another user cannot be done
+// without sudo, so -u (other than root), -g and similar options are refused.
+const sudoShim = `#!/bin/sh
+refuse() {
+    echo "sudo: $1 is not supported because sudo is not installed" >&2
+    exit 1
+}
+while [ $# -gt 0 ]; do
+    case "$1" in
+        --) shift; break ;;
+        -u|--user)
+            [ $# -ge 2 ] || refuse "$1 without a user"
+            case "$2" in root|0|"#0") ;; *) refuse "$1 $2" ;; esac
+            shift 2 ;;
+        -uroot|--user=root) shift ;;
+        -D|--chdir)
+            [ $# -ge 2 ] || refuse "$1 without a directory"
+            cd "$2" || exit 1
+            shift 2 ;;
+        -p|-C|-T|--prompt|--close-from|--command-timeout)
+            [ $# -ge 2 ] || refuse "$1 without an argument"
+            shift 2 ;;
+        -g|-U|-h|-r|-t|-R|--group|--other-user|--host|--role|--type|--chroot) refuse "$1" ;;
+        -u*|-g*|-U*|-h?*|-r*|-t*|-R*|--group=*|--other-user=*|--host=*|--role=*|--type=*|--chroot=*) refuse "$1" ;;
+        -D*|--chdir=*)
+            dir="${1#--chdir=}"
+            cd "${dir#-D}" || exit 1
+            shift ;;
+        -[!-]*[ugUhrtR]) refuse "$1" ;;
+        -[!-]*[pCT])
+            [ $# -ge 2 ] || refuse "$1 without an argument"
+            shift 2 ;;
+        -*) shift ;;
+        *) break ;;
+    esac
+done
+exec "$@"
+`
+
+// ErrSudoNotFound is returned when root privileges are needed but devenv
+// neither runs as root nor can find sudo.
+var ErrSudoNotFound = errors.New("sudo is not installed; run devenv as root or use --user for a rootless install")
+
+// Privileges describes how commands needing root can be run.
+type Privileges struct {
+	Root    bool
+	HasSudo bool
+}
+
+// DetectPrivileges inspects the effective user and whether sudo is on PATH.
+func DetectPrivileges() Privileges {
+	_, err := exec.LookPath(sudoBinary)
+	return Privileges{
+		Root:    os.Geteuid() == 0,
+		HasSudo: err == nil,
+	}
+}
+
+// Check reports ErrSudoNotFound when root commands cannot be run at all.
+func (p Privileges) Check() error {
+	if !p.Root && !p.HasSudo {
+		return ErrSudoNotFound
+	}
+	return nil
+}
+
+// SudoExecutor adapts commands to the current privileges. As root the
+// leading sudo is dropped, and when sudo is missing scripts find a
+// pass-through shim in ShimDir first on their PATH.
+type SudoExecutor struct {
+	CommandExecutor CommandExecutor
+	Privileges      Privileges
+	ShimDir         string
+}
+
+func (s *SudoExecutor) Execute(command string) error {
+	if s.Privileges.Root {
+		command = strings.TrimPrefix(command, sudoPrefix)
+		if !s.Privileges.HasSudo && s.ShimDir != "" {
+			command = fmt.Sprintf(shimPathCmd, shellQuote(s.ShimDir)) + command
+		}
+	}
+	return s.CommandExecutor.Execute(command)
+}
+
+// WriteSudoShim writes the pass-through sudo into dir.
+func WriteSudoShim(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create sudo shim directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, sudoBinary), []byte(sudoShim), 0o755); err != nil {
+		return fmt.Errorf("failed to write sudo shim: %w", err)
+	}
+	return nil
+}
+
+// SudoKeepalive validates sudo credentials once before installing, so the
+// password prompt does not interleave with installer output, and refreshes
+// them periodically during long installs.
+type SudoKeepalive struct {
+	// Validate prompts for the password if needed (sudo -v).
+	Validate func() error
+	// Refresh extends the cached credentials without prompting (sudo -n -v).
+	Refresh  func() error
+	Interval time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewSudoKeepalive() *SudoKeepalive {
+	return &SudoKeepalive{
+		Validate: func() error {
+			cmd := exec.Command(sudoBinary, sudoValidateArg)
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+			return cmd.Run()
+		},
+		Refresh: func() error {
+			return exec.Command(sudoBinary, sudoNonInteractive, sudoValidateArg).Run()
+		},
+		Interval: defaultSudoRefreshInterval,
+	}
+}
+
+// Start validates the credentials and begins refreshing them in the
+// background until Stop is called.
+func (k *SudoKeepalive) Start() error {
+	if err := k.Validate(); err != nil {
+		return fmt.Errorf("failed to validate sudo credentials: %w", err)
+	}
+
+	k.stop = make(chan struct{})
+	k.wg.Add(1)
+	go func() {
+		defer k.wg.Done()
+		ticker := time.NewTicker(k.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = k.Refresh()
+			case <-k.stop:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop ends the background refresh. It is safe to call without Start.
+func (k *SudoKeepalive) Stop() {
+	if k.stop == nil {
+		return
+	}
+	close(k.stop)
+	k.wg.Wait()
+	k.stop = nil
+}
@@ -0,0 +1,158 @@
+package installer
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSudoExecutor_ShouldDropSudoWhenRoot(t *testing.T) {
+	// Test that root runs privileged commands directly
+	mockExecutor := &MockCommandExecutor{}
+	executor := &SudoExecutor{
+		CommandExecutor: mockExecutor,
+		Privileges:      Privileges{Root: true, HasSudo: true},
+	}
+
+	if err := executor.Execute("sudo apt update"); err != nil {
+		t.Fatalf("Expected command to succeed, got: %v", err)
+	}
+
+	if mockExecutor.ExecutedCommands[0] != "apt update" {
+		t.Errorf("Expected sudo prefix to be dropped, got: %s", mockExecutor.ExecutedCommands[0])
+	}
+}
+
+func TestSudoExecutor_ShouldKeepSudoForRegularUsers(t *testing.T) {
+	// Test that non-root users still run privileged commands through sudo
+	mockExecutor := &MockCommandExecutor{}
+	executor := &SudoExecutor{
+		CommandExecutor: mockExecutor,
+		Privileges:      Privileges{HasSudo: true},
+		ShimDir:         "/tmp/shim",
+	}
+
+	if err := executor.Execute("sudo apt update"); err != nil {
+		t.Fatalf("Expected command to succeed, got: %v", err)
+	}
+
+	if mockExecutor.ExecutedCommands[0] != "sudo apt update" {
+		t.Errorf("Expected command unchanged, got: %s", mockExecutor.ExecutedCommands[0])
+	}
+}
+
+func TestSudoExecutor_ShouldPutShimOnPathWhenSudoMissing(t *testing.T) {
+	// Test that scripts calling sudo work as root without sudo installed
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	shimDir := t.TempDir()
+	if err := WriteSudoShim(shimDir); err != nil {
+		t.Fatalf("Expected shim to be written, got: %v", err)
+	}
+
+	output := filepath.Join(t.TempDir(), "out")
+	executor := &SudoExecutor{
+		CommandExecutor: &RealCommandExecutor{},
+		Privileges:      Privileges{Root: true},
+		ShimDir:         shimDir,
+	}
+
+	if err := executor.Execute("sh -c 'sudo -E touch " + output + " && echo ok > " + output + "'"); err != nil {
+		t.Fatalf("Expected command to run through the shim, got: %v", err)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil || strings.TrimSpace(string(content)) != "ok" {
+		t.Errorf("Expected command output via shim, got %q (%v)", content, err)
+	}
+}
+
+func TestSudoShim_ShouldDropOptionArgumentsAndRefuseOtherUsers(t *testing.T) {
+	// Test that the shim skips option arguments and refuses to run as another user
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	shimDir := t.TempDir()
+	if err := WriteSudoShim(shimDir); err != nil {
+		t.Fatalf("Expected shim to be written, got: %v", err)
+	}
+	executor := &SudoExecutor{
+		CommandExecutor: &RealCommandExecutor{},
+		Privileges:      Privileges{Root: true},
+		ShimDir:         shimDir,
+	}
+
+	output := filepath.Join(t.TempDir(), "out")
+	if err := executor.Execute("sh -c 'sudo -u root -p prompt touch " + output + "'"); err != nil {
+		t.Fatalf("Expected options and their arguments to be dropped, got: %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("Expected command to run through the shim, got: %v", err)
+	}
+
+	for _, command := range []string{"sudo -u postgres true", "sudo -Eu postgres true", "sudo -g docker true"} {
+		if err := executor.Execute("sh -c '" + command + "'"); err == nil {
+			t.Errorf("Expected %q to be refused without sudo", command)
+		}
+	}
+}
+
+func TestPrivileges_ShouldRequireRootOrSudo(t *testing.T) {
+	if err := (Privileges{}).Check(); !errors.Is(err, ErrSudoNotFound) {
+		t.Errorf("Expected ErrSudoNotFound, got: %v", err)
+	}
+	if err := (Privileges{Root: true}).Check(); err != nil {
+		t.Errorf("Expected root without sudo to be accepted, got: %v", err)
+	}
+	if err := (Privileges{HasSudo: true}).Check(); err != nil {
+		t.Errorf("Expected sudo user to be accepted, got: %v", err)
+	}
+}
+
+func TestSudoKeepalive_ShouldValidateOnceAndRefresh(t *testing.T) {
+	// Test that credentials are validated up front and refreshed in the background
+	var validations, refreshes atomic.Int32
+	keepalive := &SudoKeepalive{
+		Validate: func() error { validations.Add(1); return nil },
+		Refresh:  func() error { refreshes.Add(1); return nil },
+		Interval: time.Millisecond,
+	}
+
+	if err := keepalive.Start(); err != nil {
+		t.Fatalf("Expected keepalive to start, got: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for refreshes.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	keepalive.Stop()
+
+	if validations.Load() != 1 {
+		t.Errorf("Expected exactly one validation, got %d", validations.Load())
+	}
+	if refreshes.Load() == 0 {
+		t.Errorf("Expected credentials to be refreshed")
+	}
+}
+
+func TestSudoKeepalive_ShouldFailWhenValidationFails(t *testing.T) {
+	keepalive := &SudoKeepalive{
+		Validate: func() error { return errors.New("incorrect password") },
+		Refresh:  func() error { return nil },
+		Interval: time.Millisecond,
+	}
+
+	if err := keepalive.Start(); err == nil {
+		t.Errorf("Expected validation failure to be reported")
+	}
+	keepalive.Stop()
+}
@@ -0,0 +1,162 @@
+package keyring
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	armorHeader = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	armorFooter = "-----END PGP PUBLIC KEY BLOCK-----"
+
+	tagPublicKey   = 6
+	keyVersion4    = 4
+	fingerprintTag = 0x99
+)
+
+// ErrNoPublicKey is returned when the data contains no usable public key.
+var ErrNoPublicKey = errors.New("no OpenPGP public key found")
+
+// IsArmored reports whether data is an ASCII armored key (.asc) rather than
+// a binary keyring (.gpg).
+func IsArmored(data []byte) bool {
+	return bytes.Contains(data, []byte(armorHeader))
+}
+
+// Fingerprints returns the fingerprints of the primary keys in an armored or
+// binary OpenPGP public key, as upper-case hex without spaces.
+func Fingerprints(data []byte) ([]string, error) {
+	if IsArmored(data) {
+		decoded, err := dearmor(data)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+
+	var fingerprints []string
+	for len(data) > 0 {
+		tag, body, rest, err := nextPacket(data)
+		if err != nil {
+			return nil, err
+		}
+		data = rest
+
+		if tag != tagPublicKey || len(body) == 0 || body[0] != keyVersion4 {
+			continue
+		}
+
+		hash := sha1.New()
+		hash.Write([]byte{fingerprintTag, byte(len(body) >> 8), byte(len(body))})
+		hash.Write(body)
+		fingerprints = append(fingerprints, strings.ToUpper(hex.EncodeToString(hash.Sum(nil))))
+	}
+
+	if len(fingerprints) == 0 {
+		return nil, ErrNoPublicKey
+	}
+	return fingerprints, nil
+}
+
+// NormalizeFingerprint removes spaces and upper-cases fingerprint so it can be
+// compared with Fingerprints.
+func NormalizeFingerprint(fingerprint string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(fingerprint), ""))
+}
+
+// dearmor extracts the base64 payload between the armor header lines and the
+// CRC line.
+func dearmor(data []byte) ([]byte, error) {
+	text := string(data)
+	start := strings.Index(text, armorHeader)
+	end := strings.Index(text, armorFooter)
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("malformed armored key")
+	}
+
+	lines := strings.Split(text[start+len(armorHeader):end], "\n")
+	var payload strings.Builder
+	inHeaders := true
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		switch {
+		case inHeaders:
+			// Armor headers such as "Comment: ..." end at the first blank line.
+			inHeaders = line != ""
+		case strings.HasPrefix(line, "="):
+			// The CRC24 checksum line is not part of the payload.
+		default:
+			payload.WriteString(line)
+		}
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(payload.String())
+	if err != nil {
+		return nil, fmt.Errorf("malformed armored key: %w", err)
+	}
+	return decoded, nil
+}
+
+// nextPacket splits the first OpenPGP packet off data (RFC 4880 section 4.2).
+func nextPacket(data []byte) (tag int, body, rest []byte, err error) {
+	header := data[0]
+	if header&0x80 == 0 {
+		return 0, nil, nil, fmt.Errorf("invalid OpenPGP packet header")
+	}
+
+	var length, offset int
+	if header&0x40 != 0 {
+		tag = int(header & 0x3f)
+		if len(data) < 2 {
+			return 0, nil, nil, fmt.Errorf("truncated OpenPGP packet")
+		}
+		switch first := int(data[1]); {
+		case first < 192:
+			length, offset = first, 2
+		case first < 224:
+			if len(data) < 3 {
+				return 0, nil, nil, fmt.Errorf("truncated OpenPGP packet")
+			}
+			length, offset = (first-192)<<8+int(data[2])+192, 3
+		case first == 255:
+			if len(data) < 6 {
+				return 0, nil, nil, fmt.Errorf("truncated OpenPGP packet")
+			}
+			length, offset = int(binary.BigEndian.Uint32(data[2:6])), 6
+		default:
+			return 0, nil, nil, fmt.Errorf("partial OpenPGP packet lengths are not supported in keys")
+		}
+	} else {
+		tag = int(header>>2) & 0x0f
+		switch header & 0x03 {
+		case 0:
+			if len(data) < 2 {
+				return 0, nil, nil, fmt.Errorf("truncated OpenPGP packet")
+			}
+			length, offset = int(data[1]), 2
+		case 1:
+			if len(data) < 3 {
+				return 0, nil, nil, fmt.Errorf("truncated OpenPGP packet")
+			}
+			length, offset = int(binary.BigEndian.Uint16(data[1:3])), 3
+		case 2:
+			if len(data) < 5 {
+				return 0, nil, nil, fmt.Errorf("truncated OpenPGP packet")
+			}
+			length, offset = int(binary.BigEndian.Uint32(data[1:5])), 5
+		default:
+			length, offset = len(data)-1, 1
+		}
+	}
+
+	if offset+length > len(data) {
+		return 0, nil, nil, fmt.Errorf("truncated OpenPGP packet")
+	}
+	return tag, data[offset : offset+length], data[offset+length:], nil
+}
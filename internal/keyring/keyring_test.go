@@ -0,0 +1,78 @@
+package keyring
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testKey is an ed25519 public key generated with gpg for these tests.
+const testKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mDMEatVOBxYJKwYBBAHaRw8BAQdABpI5U3ZbQT0XFG95fnuri+EAZe42Mo5Ckizh
+LVfswMm0HmRldmVudiB0ZXN0IDx0ZXN0QGV4YW1wbGUuY29tPoiQBBMWCAA4FiEE
+RnusQDy3hXPg71w4hpfIB/Fqw0oFAmrVTgcCGwEFCwkIBwIGFQoJCAsCBBYCAwEC
+HgECF4AACgkQhpfIB/Fqw0rYmAD/X8mlgT3dy+bi89fYlpdw0+CVyRL6Lqz3vGyD
+KUsU4gkBAJTGVFQ4yFDWXOo3E2SJfAK5YTsoXUGeVMQLU9J6LgcL
+=c1Ia
+-----END PGP PUBLIC KEY BLOCK-----`
+
+const testFingerprint = "467BAC403CB78573E0EF5C388697C807F16AC34A"
+
+func TestFingerprints_ShouldMatchGPGForArmoredKey(t *testing.T) {
+	fingerprints, err := Fingerprints([]byte(testKey))
+	if err != nil {
+		t.Fatalf("Expected fingerprint, got error: %v", err)
+	}
+
+	if len(fingerprints) != 1 || fingerprints[0] != testFingerprint {
+		t.Errorf("Expected %s, got %v", testFingerprint, fingerprints)
+	}
+}
+
+func TestFingerprints_ShouldMatchGPGForBinaryKey(t *testing.T) {
+	binary := dearmorForTest(t, testKey)
+	if IsArmored(binary) {
+		t.Fatalf("Expected binary key not to be detected as armored")
+	}
+
+	fingerprints, err := Fingerprints(binary)
+	if err != nil || len(fingerprints) != 1 || fingerprints[0] != testFingerprint {
+		t.Errorf("Expected %s, got %v (%v)", testFingerprint, fingerprints, err)
+	}
+}
+
+func TestFingerprints_ShouldRejectNonKeyData(t *testing.T) {
+	if _, err := Fingerprints([]byte("<html>404 Not Found</html>")); err == nil {
+		t.Errorf("Expected error for non-key data")
+	}
+
+	// A user ID packet alone carries no public key
+	if _, err := Fingerprints([]byte{0xcd, 0x01, 'x'}); !errors.Is(err, ErrNoPublicKey) {
+		t.Errorf("Expected ErrNoPublicKey, got: %v", err)
+	}
+}
+
+func TestNormalizeFingerprint_ShouldStripSpaces(t *testing.T) {
+	got := NormalizeFingerprint("467b ac40 3cb7 8573 e0ef  5c38 8697 c807 f16a c34a")
+	if got != testFingerprint {
+		t.Errorf("Expected %s, got %s", testFingerprint, got)
+	}
+}
+
+func dearmorForTest(t *testing.T, armored string) []byte {
+	t.Helper()
+	var payload strings.Builder
+	for _, line := range strings.Split(armored, "\n")[2:] {
+		if strings.HasPrefix(line, "=") || strings.HasPrefix(line, "-----") {
+			break
+		}
+		payload.WriteString(line)
+	}
+	data, err := base64.StdEncoding.DecodeString(payload.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
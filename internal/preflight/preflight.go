@@ -0,0 +1,400 @@
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode"
+
+	"github.com/petersenjoern/devenv/internal/config"
+)
+
+const (
+	defaultMinFreeBytes = 512 << 20
+	defaultInstallDir   = "/usr/local/bin"
+	accessWriteOK       = 0x2 // W_OK for access(2)
+	sudoBinary          = "sudo"
+	fuserBinary         = "fuser"
+)
+
+// Check names used in problem reports.
+const (
+	CheckLock     = "package lock"
+	CheckDisk     = "disk space"
+	CheckScript   = "install script"
+	CheckBinary   = "required binary"
+	CheckWritable = "target directory"
+)
+
+// DefaultLockFiles are the locks apt and dpkg hold while they run.
+var DefaultLockFiles = []string{
+	"/var/lib/dpkg/lock-frontend",
+	"/var/lib/dpkg/lock",
+	"/var/lib/apt/lists/lock",
+}
+
+// requiredBinaries lists the commands each install method relies on.
+var requiredBinaries = map[string][]string{
+	"apt":      {"apt"},
+	"script":   {"bash", "tar"},
+	"download": {"install"},
+	"git":      {"git"},
+	"steps":    {"install"},
+}
+
+// privilegedMethods run commands through sudo unless devenv runs as root or
+// in --user mode.
+var privilegedMethods = map[string]bool{"apt": true, "script": true, "download": true, "steps": true}
+
+// Problem is a single failed preflight check.
+type Problem struct {
+	Tool    string
+	Check   string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Tool == "" {
+		return fmt.Sprintf("%s: %s", p.Check, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Tool, p.Check, p.Message)
+}
+
+// Error combines every problem found so they can be fixed in one go.
+type Error struct {
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "preflight checks failed (%d problems):", len(e.Problems))
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&report, "\n  - %s", problem)
+	}
+	return report.String()
+}
+
+// Checker verifies that a plan can be installed before anything is changed.
+type Checker struct {
+	// Arch skips tools that are unsupported on this architecture anyway.
+	Arch string
+	// Root and UserMode decide whether sudo is needed and whether target
+	// directories are written directly.
+	Root     bool
+	UserMode bool
+	// Prefix is where scripts and downloads install to (/usr/local or ~/.local).
+	Prefix string
+
+	MinFreeBytes uint64
+	LockFiles    []string
+
+	LookPath     func(file string) (string, error)
+	ScriptExists func(path string) bool
+	FreeBytes    func(path string) (uint64, error)
+	Writable     func(path string) bool
+	// LockHolder returns the pid holding the lock file, if any. An error
+	// means the lock could not be checked.
+	LockHolder func(path string) (int, bool, error)
+	// Warn reports checks that could not be run; nil drops the warnings.
+	Warn func(message string)
+}
+
+func New() *Checker {
+	return &Checker{
+		Root:         os.Geteuid() == 0,
+		Prefix:       "/usr/local",
+		MinFreeBytes: defaultMinFreeBytes,
+		LockFiles:    DefaultLockFiles,
+		LookPath:     exec.LookPath,
+		ScriptExists: fileExists,
+		FreeBytes:    freeBytes,
+		Writable:     writable,
+		LockHolder:   lockHolder,
+	}
+}
+
+// Run checks every tool in plan and returns an *Error listing all problems.
+func (c *Checker) Run(plan []string, tools map[string]config.ToolConfig) error {
+	var problems []Problem
+	methods := make(map[string]bool)
+	missingBinaries := make(map[string]bool)
+	targetDirs := make(map[string]bool)
+
+	for _, toolName := range plan {
+		tool := tools[toolName]
+		if !config.SupportsArchitecture(tool, c.Arch) {
+			continue
+		}
+		if len(tool.InstallMethods) > 0 {
+			// Fallback chains skip unusable methods at install time, so
+			// check the first method that can run here.
+			tool.InstallMethod = c.firstAvailable(tool.InstallMethods)
+		}
+		if tool.InstallMethod == "apt" && c.UserMode {
+			continue
+		}
+
+		switch tool.InstallMethod {
+		case "script":
+			if !c.ScriptExists(tool.InstallScript) {
+				problems = append(problems, Problem{toolName, CheckScript, fmt.Sprintf("%s not found", tool.InstallScript)})
+			}
+			targetDirs[filepath.Join(c.Prefix, "bin")] = true
+		case "download":
+			targetDirs[c.downloadDir(tool)] = true
+		case "apt":
+			// Needs only the package manager and its lock.
+		case "git":
+			// Clones into the user's home; needs only git.
+		case "steps":
+			targetDirs[filepath.Join(c.Prefix, "bin")] = true
+			if hasPackageSteps(tool) && !c.UserMode {
+				methods["apt"] = true
+			}
+		default:
+			continue
+		}
+
+		methods[tool.InstallMethod] = true
+		for _, binary := range c.binariesFor(tool.InstallMethod) {
+			if missingBinaries[binary] {
+				continue
+			}
+			if _, err := c.LookPath(binary); err != nil {
+				missingBinaries[binary] = true
+				problems = append(problems, Problem{toolName, CheckBinary, fmt.Sprintf("%s not found in PATH", binary)})
+			}
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil
+	}
+
+	if methods["apt"] || methods["script"] {
+		problems = append(problems, c.checkLocks()...)
+	}
+
+	problems = append(problems, c.checkDisk(targetDirs)...)
+	problems = append(problems, c.checkWritable(targetDirs)...)
+
+	if len(problems) > 0 {
+		return &Error{Problems: problems}
+	}
+	return nil
+}
+
+// MethodAvailable reports whether the commands an install method relies on
+// are present, so fallback chains can skip methods this machine cannot run.
+func (c *Checker) MethodAvailable(method string) bool {
+	if method == "apt" && c.UserMode {
+		return false
+	}
+	for _, binary := range c.binariesFor(method) {
+		if _, err := c.LookPath(binary); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// firstAvailable returns the first of methods MethodAvailable accepts, or ""
+// when none does.
+func (c *Checker) firstAvailable(methods []string) string {
+	for _, method := range methods {
+		if c.MethodAvailable(method) {
+			return method
+		}
+	}
+	return ""
+}
+
+func hasPackageSteps(tool config.ToolConfig) bool {
+	for _, step := range tool.Steps {
+		if step.Action == config.StepPackage {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) binariesFor(method string) []string {
+	binaries := requiredBinaries[method]
+	if privilegedMethods[method] && !c.Root && !c.UserMode {
+		binaries = append([]string{"sudo"}, binaries...)
+	}
+	return binaries
+}
+
+func (c *Checker) downloadDir(tool config.ToolConfig) string {
+	location := filepath.Join(defaultInstallDir, tool.BinaryName)
+	if tool.InstallLocation != "" {
+		location = tool.InstallLocation
+	}
+	if c.UserMode {
+		return filepath.Join(c.Prefix, "bin")
+	}
+	return filepath.Dir(location)
+}
+
+func (c *Checker) checkLocks() []Problem {
+	var problems []Problem
+	for _, lockFile := range c.LockFiles {
+		pid, held, err := c.LockHolder(lockFile)
+		if err != nil {
+			c.warn(fmt.Sprintf("%s: could not check %s: %v", CheckLock, lockFile, err))
+			continue
+		}
+		if held {
+			problems = append(problems, Problem{"", CheckLock, fmt.Sprintf("%s is held by pid %d; wait for the other package manager to finish", lockFile, pid)})
+		}
+	}
+	return problems
+}
+
+func (c *Checker) warn(message string) {
+	if c.Warn != nil {
+		c.Warn(message)
+	}
+}
+
+func (c *Checker) checkDisk(targetDirs map[string]bool) []Problem {
+	var problems []Problem
+	paths := append(sortedKeys(targetDirs), os.TempDir())
+	checked := make(map[string]bool)
+
+	for _, path := range paths {
+		existing := existingParent(path)
+		if checked[existing] {
+			continue
+		}
+		checked[existing] = true
+
+		free, err := c.FreeBytes(existing)
+		if err != nil {
+			continue
+		}
+		if free < c.MinFreeBytes {
+			problems = append(problems, Problem{"", CheckDisk, fmt.Sprintf("only %d MiB free on %s, need at least %d MiB", free>>20, existing, c.MinFreeBytes>>20)})
+		}
+	}
+	return problems
+}
+
+// checkWritable only applies when devenv writes directly, i.e. as root or in
+// --user mode; otherwise writes go through sudo.
+func (c *Checker) checkWritable(targetDirs map[string]bool) []Problem {
+	if !c.Root && !c.UserMode {
+		return nil
+	}
+
+	var problems []Problem
+	for _, dir := range sortedKeys(targetDirs) {
+		existing := existingParent(dir)
+		if !c.Writable(existing) {
+			problems = append(problems, Problem{"", CheckWritable, fmt.Sprintf("%s is not writable", existing)})
+		}
+	}
+	return problems
+}
+
+// existingParent returns path or its closest existing ancestor, which is
+// what will be created into.
+func existingParent(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func freeBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
+
+func writable(path string) bool {
+	return syscall.Access(path, accessWriteOK) == nil
+}
+
+// lockHolder asks the kernel which process holds the fcntl lock apt and dpkg
+// use. The lock files are readable only by root, so other users ask fuser
+// through sudo instead.
+func lockHolder(path string) (int, bool, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if errors.Is(err, os.ErrPermission) {
+		return sudoLockHolder(path)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	defer file.Close()
+
+	lock := syscall.Flock_t{Type: syscall.F_WRLCK}
+	if err := syscall.FcntlFlock(file.Fd(), syscall.F_GETLK, &lock); err != nil {
+		return 0, false, err
+	}
+	if lock.Type == syscall.F_UNLCK {
+		return 0, false, nil
+	}
+	return int(lock.Pid), true, nil
+}
+
+// sudoLockHolder reports the first process fuser finds using path. It
+// needs sudo credentials that are already cached, as it must not prompt.
+func sudoLockHolder(path string) (int, bool, error) {
+	if _, err := exec.LookPath(fuserBinary); err != nil {
+		return 0, false, fmt.Errorf("permission denied and %s is not installed", fuserBinary)
+	}
+	if err := exec.Command(sudoBinary, "-n", "true").Run(); err != nil {
+		return 0, false, fmt.Errorf("permission denied and sudo needs a password")
+	}
+
+	// fuser prints the pids on stdout and exits 1 when no process uses path.
+	output, err := exec.Command(sudoBinary, "-n", fuserBinary, path).Output()
+	pids := strings.Fields(string(output))
+	var exitErr *exec.ExitError
+	if len(pids) == 0 && errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("%s failed: %w", fuserBinary, err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimRightFunc(pids[0], unicode.IsLetter))
+	if err != nil {
+		return 0, false, fmt.Errorf("unexpected %s output %q", fuserBinary, output)
+	}
+	return pid, true, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,149 @@
+package preflight
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/petersenjoern/devenv/internal/config"
+)
+
+func newTestChecker() *Checker {
+	return &Checker{
+		Arch:         "amd64",
+		Prefix:       "/usr/local",
+		MinFreeBytes: 100,
+		LockFiles:    []string{"/var/lib/dpkg/lock-frontend"},
+		LookPath:     func(file string) (string, error) { return "/usr/bin/" + file, nil },
+		ScriptExists: func(string) bool { return true },
+		FreeBytes:    func(string) (uint64, error) { return 1000, nil },
+		Writable:     func(string) bool { return true },
+		LockHolder:   func(string) (int, bool, error) { return 0, false, nil },
+	}
+}
+
+var testTools = map[string]config.ToolConfig{
+	"git":    {BinaryName: "git", InstallMethod: "apt", PackageName: "git"},
+	"docker": {BinaryName: "docker", InstallMethod: "script", InstallScript: "install_scripts/docker.sh"},
+	"broot":  {BinaryName: "broot", InstallMethod: "download", DownloadURL: "https://example.com/broot"},
+	"alacritty": {
+		BinaryName:    "alacritty",
+		InstallMethod: "manual",
+	},
+}
+
+func TestChecker_ShouldPassWhenEverythingIsAvailable(t *testing.T) {
+	checker := newTestChecker()
+
+	if err := checker.Run([]string{"git", "docker", "broot"}, testTools); err != nil {
+		t.Errorf("Expected preflight to pass, got: %v", err)
+	}
+}
+
+func TestChecker_ShouldCombineAllProblems(t *testing.T) {
+	// Test that every problem is reported at once instead of failing on the first
+	checker := newTestChecker()
+	checker.ScriptExists = func(string) bool { return false }
+	checker.LookPath = func(file string) (string, error) {
+		if file == "sudo" {
+			return "", exec.ErrNotFound
+		}
+		return "/usr/bin/" + file, nil
+	}
+	checker.FreeBytes = func(string) (uint64, error) { return 10, nil }
+	checker.LockHolder = func(string) (int, bool, error) { return 4242, true, nil }
+
+	err := checker.Run([]string{"git", "docker"}, testTools)
+
+	var preflightErr *Error
+	if !errors.As(err, &preflightErr) {
+		t.Fatalf("Expected *preflight.Error, got: %v", err)
+	}
+
+	checks := make(map[string]bool)
+	for _, problem := range preflightErr.Problems {
+		checks[problem.Check] = true
+	}
+	for _, check := range []string{CheckScript, CheckBinary, CheckDisk, CheckLock} {
+		if !checks[check] {
+			t.Errorf("Expected a %q problem, got: %v", check, err)
+		}
+	}
+
+	if !strings.Contains(err.Error(), "pid 4242") {
+		t.Errorf("Expected lock holder pid in report, got: %v", err)
+	}
+	if strings.Count(err.Error(), "sudo not found") != 1 {
+		t.Errorf("Expected missing sudo to be reported once, got: %v", err)
+	}
+}
+
+func TestChecker_ShouldSkipSudoAndAptInUserMode(t *testing.T) {
+	// Test that rootless installs need neither sudo nor apt
+	checker := newTestChecker()
+	checker.UserMode = true
+	checker.Prefix = "/home/dev/.local"
+	checker.LookPath = func(file string) (string, error) {
+		if file == "sudo" || file == "apt" {
+			return "", exec.ErrNotFound
+		}
+		return "/usr/bin/" + file, nil
+	}
+
+	if err := checker.Run([]string{"git", "broot"}, testTools); err != nil {
+		t.Errorf("Expected user mode preflight to pass, got: %v", err)
+	}
+}
+
+func TestChecker_ShouldReportUnwritableTargetWhenRoot(t *testing.T) {
+	checker := newTestChecker()
+	checker.Root = true
+	checker.Writable = func(string) bool { return false }
+
+	err := checker.Run([]string{"broot"}, testTools)
+	if err == nil || !strings.Contains(err.Error(), CheckWritable) {
+		t.Errorf("Expected unwritable target directory problem, got: %v", err)
+	}
+}
+
+func TestChecker_ShouldIgnoreManualTools(t *testing.T) {
+	checker := newTestChecker()
+	checker.FreeBytes = func(string) (uint64, error) { return 0, nil }
+
+	if err := checker.Run([]string{"alacritty"}, testTools); err != nil {
+		t.Errorf("Expected manual-only plan to skip preflight, got: %v", err)
+	}
+}
+
+func TestChecker_ShouldCheckFirstAvailableMethodOfFallbackChain(t *testing.T) {
+	// Test that fallback chain tools get the lock and disk checks of the method they will use
+	checker := newTestChecker()
+	checker.LockHolder = func(string) (int, bool, error) { return 4242, true, nil }
+	checker.FreeBytes = func(string) (uint64, error) { return 10, nil }
+	tools := map[string]config.ToolConfig{
+		"bat": {BinaryName: "bat", InstallMethods: []string{"apt", "steps"}, PackageName: "bat"},
+	}
+
+	err := checker.Run([]string{"bat"}, tools)
+	if err == nil || !strings.Contains(err.Error(), "pid 4242") || !strings.Contains(err.Error(), CheckDisk) {
+		t.Errorf("Expected apt lock and disk problems for the chain, got: %v", err)
+	}
+}
+
+func TestChecker_ShouldWarnWhenLockCannotBeChecked(t *testing.T) {
+	// Test that an unreadable package lock is reported as a warning instead of passing silently
+	checker := newTestChecker()
+	checker.LockHolder = func(string) (int, bool, error) {
+		return 0, false, errors.New("permission denied and sudo needs a password")
+	}
+	var warnings []string
+	checker.Warn = func(message string) { warnings = append(warnings, message) }
+
+	if err := checker.Run([]string{"git"}, testTools); err != nil {
+		t.Fatalf("Expected an unchecked lock not to fail preflight, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "could not check /var/lib/dpkg/lock-frontend") {
+		t.Errorf("Expected a warning about the unchecked lock, got %v", warnings)
+	}
+}
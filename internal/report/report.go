@@ -0,0 +1,201 @@
+// Package report serializes install results for CI systems.
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/installer"
+)
+
+// Report formats.
+const (
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+const junitSuiteName = "devenv install"
+
+// ErrUnknownFormat marks report formats other than json and junit.
+var ErrUnknownFormat = errors.New("unknown report format")
+
+// Tool is the outcome of a single tool in a JSON report.
+type Tool struct {
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name"`
+	Status      string    `json:"status"`
+	Method      string    `json:"method,omitempty"`
+	Version     string    `json:"version,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	Duration    float64   `json:"duration_seconds"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error,omitempty"`
+	Output      string    `json:"output,omitempty"`
+}
+
+// Report is the JSON report: every tool in install order and how many
+// ended in each status.
+type Report struct {
+	Tools   []Tool         `json:"tools"`
+	Summary map[string]int `json:"summary"`
+}
+
+// CheckFormat returns an error unless format is a supported report format.
+func CheckFormat(format string) error {
+	switch format {
+	case FormatJSON, FormatJUnit:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s (use %s or %s)", ErrUnknownFormat, format, FormatJSON, FormatJUnit)
+	}
+}
+
+// Write serializes results to w in format.
+func Write(w io.Writer, format string, results map[string]installer.InstallationResult) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, results)
+	case FormatJUnit:
+		return WriteJUnit(w, results)
+	default:
+		return CheckFormat(format)
+	}
+}
+
+// New builds the report of results.
+func New(results map[string]installer.InstallationResult) Report {
+	report := Report{Tools: []Tool{}, Summary: make(map[string]int)}
+	for _, name := range installer.InOrder(results) {
+		result := results[name]
+		tool := Tool{
+			Name:        name,
+			DisplayName: result.Tool.DisplayName,
+			Status:      string(result.Status),
+			Method:      result.Method,
+			Version:     result.Version,
+			StartedAt:   result.StartedAt,
+			FinishedAt:  result.FinishedAt,
+			Duration:    result.Duration().Seconds(),
+			Attempts:    len(result.Attempts),
+			Output:      result.Output,
+		}
+		if result.Error != nil {
+			tool.Error = result.Error.Error()
+		}
+		report.Tools = append(report.Tools, tool)
+		report.Summary[tool.Status]++
+	}
+	return report
+}
+
+func WriteJSON(w io.Writer, results map[string]installer.InstallationResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(New(results)); err != nil {
+		return fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	return nil
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as a JUnit XML test suite with one test case per
+// tool. Failed and cancelled tools are failures; skipped tools and pending
+// manual steps are skipped.
+func WriteJUnit(w io.Writer, results map[string]installer.InstallationResult) error {
+	suite := junitSuite{Name: junitSuiteName}
+
+	var total time.Duration
+	for _, name := range installer.InOrder(results) {
+		result := results[name]
+		testCase := junitCase{
+			Name:      name,
+			ClassName: "devenv." + classFor(result),
+			Time:      seconds(result.Duration()),
+			SystemOut: result.Output,
+		}
+
+		message := string(result.Status)
+		if result.Error != nil {
+			message = result.Error.Error()
+		}
+
+		switch result.Status {
+		case installer.StatusFailed, installer.StatusCancelled:
+			testCase.Failure = &junitMessage{Message: message, Type: string(result.Status), Text: result.Output}
+			testCase.SystemOut = ""
+			suite.Failures++
+		case installer.StatusSkipped, installer.StatusManualPending:
+			testCase.Skipped = &junitMessage{Message: message}
+			suite.Skipped++
+		}
+
+		if suite.Timestamp == "" && !result.StartedAt.IsZero() {
+			suite.Timestamp = result.StartedAt.UTC().Format(time.RFC3339)
+		}
+		total += result.Duration()
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(suite.Cases)
+	suite.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// classFor groups test cases by install method, so CI views list apt,
+// script and download tools together.
+func classFor(result installer.InstallationResult) string {
+	if result.Method != "" {
+		return result.Method
+	}
+	if result.Tool.InstallMethod != "" {
+		return result.Tool.InstallMethod
+	}
+	return "tool"
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/config"
+	"github.com/petersenjoern/devenv/internal/installer"
+)
+
+func sampleResults() map[string]installer.InstallationResult {
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return map[string]installer.InstallationResult{
+		"git": {
+			Tool:       config.ToolConfig{DisplayName: "Git"},
+			Status:     installer.StatusInstalled,
+			Method:     "apt",
+			Version:    "git version 2.43.0",
+			Order:      0,
+			StartedAt:  started,
+			FinishedAt: started.Add(1500 * time.Millisecond),
+			Attempts:   []installer.Attempt{{}},
+		},
+		"broot": {
+			Tool:       config.ToolConfig{DisplayName: "Broot"},
+			Status:     installer.StatusFailed,
+			Method:     "download",
+			Error:      errors.New("failed to download Broot: 404"),
+			Output:     "curl: (22) 404",
+			Order:      1,
+			StartedAt:  started.Add(2 * time.Second),
+			FinishedAt: started.Add(3 * time.Second),
+			Attempts:   []installer.Attempt{{}, {}},
+		},
+		"alacritty": {
+			Tool:   config.ToolConfig{DisplayName: "Alacritty", InstallMethod: "manual"},
+			Status: installer.StatusManualPending,
+			Error:  installer.ErrManualActionPending,
+			Order:  2,
+		},
+	}
+}
+
+// Test that the JSON report lists tools in install order with status, error and duration
+func TestWriteJSON_ShouldListToolsInInstallOrder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, sampleResults()); err != nil {
+		t.Fatalf("Expected report to be written, got: %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+
+	if len(report.Tools) != 3 || report.Tools[0].Name != "git" || report.Tools[1].Name != "broot" {
+		t.Fatalf("Expected tools in install order, got %+v", report.Tools)
+	}
+	if report.Tools[0].Duration != 1.5 || report.Tools[0].Version != "git version 2.43.0" {
+		t.Errorf("Expected duration and version of git, got %+v", report.Tools[0])
+	}
+	if report.Tools[1].Error != "failed to download Broot: 404" || report.Tools[1].Attempts != 2 {
+		t.Errorf("Expected error and attempts of broot, got %+v", report.Tools[1])
+	}
+	if report.Summary["installed"] != 1 || report.Summary["failed"] != 1 || report.Summary["manual-pending"] != 1 {
+		t.Errorf("Expected counts per status, got %v", report.Summary)
+	}
+}
+
+// Test that the JUnit report marks failed tools as failures and pending ones as skipped
+func TestWriteJUnit_ShouldReportFailuresAndSkips(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJUnit, sampleResults()); err != nil {
+		t.Fatalf("Expected report to be written, got: %v", err)
+	}
+
+	var suites junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("Expected valid XML, got: %v\n%s", err, buf.String())
+	}
+
+	suite := suites.Suites[0]
+	if suite.Tests != 3 || suite.Failures != 1 || suite.Skipped != 1 || suite.Time != "2.500" {
+		t.Errorf("Expected 3 tests, 1 failure, 1 skip in 2.5s, got %+v", suite)
+	}
+	broot := suite.Cases[1]
+	if broot.Name != "broot" || broot.ClassName != "devenv.download" || broot.Failure == nil {
+		t.Fatalf("Expected broot to be a failed download test case, got %+v", broot)
+	}
+	if broot.Failure.Message != "failed to download Broot: 404" || !strings.Contains(broot.Failure.Text, "404") {
+		t.Errorf("Expected failure message and output, got %+v", broot.Failure)
+	}
+	if suite.Cases[2].Skipped == nil || suite.Cases[2].ClassName != "devenv.manual" {
+		t.Errorf("Expected pending manual tool to be skipped, got %+v", suite.Cases[2])
+	}
+}
+
+// Test that unknown formats are rejected
+func TestCheckFormat_ShouldRejectUnknownFormats(t *testing.T) {
+	if err := CheckFormat("yaml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got: %v", err)
+	}
+	if err := CheckFormat(FormatJUnit); err != nil {
+		t.Errorf("Expected junit to be accepted, got: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const checkpointFile = "checkpoint.json"
+
+// ErrNoCheckpoint is returned when there is no interrupted run to resume.
+var ErrNoCheckpoint = errors.New("no interrupted install to resume")
+
+// Checkpoint records the plan of an install run and which tools are
+// finished, so an interrupted run can continue where it stopped.
+type Checkpoint struct {
+	StartedAt  time.Time `json:"started_at"`
+	ConfigPath string    `json:"config_path"`
+	BundleDir  string    `json:"bundle_dir,omitempty"`
+	UserMode   bool      `json:"user_mode,omitempty"`
+	Plan       []string  `json:"plan"`
+	Completed  []string  `json:"completed"`
+
+	dir string
+	mu  sync.Mutex
+}
+
+// StartCheckpoint writes a new checkpoint for plan into dir. bundleDir is
+// the offline bundle the run installs from, empty when it uses the network.
+func StartCheckpoint(dir, configPath, bundleDir string, userMode bool, plan []string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{
+		StartedAt:  time.Now().UTC(),
+		ConfigPath: configPath,
+		BundleDir:  bundleDir,
+		UserMode:   userMode,
+		Plan:       plan,
+		Completed:  []string{},
+		dir:        dir,
+	}
+	if err := checkpoint.save(); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// LoadCheckpoint reads the checkpoint of an interrupted run from dir.
+func LoadCheckpoint(dir string) (*Checkpoint, error) {
+	data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCheckpoint
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	checkpoint := &Checkpoint{dir: dir}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// IsDone reports whether toolName finished in this or an earlier attempt.
+func (c *Checkpoint) IsDone(toolName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Contains(c.Completed, toolName)
+}
+
+// MarkDone records toolName as finished and persists the checkpoint.
+func (c *Checkpoint) MarkDone(toolName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if slices.Contains(c.Completed, toolName) {
+		return nil
+	}
+	c.Completed = append(c.Completed, toolName)
+	return c.save()
+}
+
+// Remaining returns the planned tools that are not finished yet, in order.
+func (c *Checkpoint) Remaining() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var remaining []string
+	for _, toolName := range c.Plan {
+		if !slices.Contains(c.Completed, toolName) {
+			remaining = append(remaining, toolName)
+		}
+	}
+	return remaining
+}
+
+// Clear removes the checkpoint once every planned tool is finished.
+func (c *Checkpoint) Clear() error {
+	err := os.Remove(filepath.Join(c.dir, checkpointFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (c *Checkpoint) save() error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp := filepath.Join(c.dir, checkpointFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(c.dir, checkpointFile))
+}
@@ -0,0 +1,43 @@
+package state
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckpoint_ShouldPersistProgressAcrossRuns(t *testing.T) {
+	dir := t.TempDir()
+	plan := []string{"curl", "docker", "lazydocker"}
+
+	checkpoint, err := StartCheckpoint(dir, "/etc/devenv/config.yaml", "", true, plan)
+	if err != nil {
+		t.Fatalf("Expected checkpoint to start, got: %v", err)
+	}
+	if err := checkpoint.MarkDone("curl"); err != nil {
+		t.Fatal(err)
+	}
+
+	// An interrupted run is picked up by a later invocation from disk
+	loaded, err := LoadCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("Expected checkpoint to load, got: %v", err)
+	}
+
+	if !loaded.IsDone("curl") || loaded.IsDone("docker") {
+		t.Errorf("Expected only curl to be done, got %v", loaded.Completed)
+	}
+	if !reflect.DeepEqual(loaded.Remaining(), []string{"docker", "lazydocker"}) {
+		t.Errorf("Expected docker and lazydocker to remain, got %v", loaded.Remaining())
+	}
+	if loaded.ConfigPath != "/etc/devenv/config.yaml" || !loaded.UserMode {
+		t.Errorf("Expected config path and mode to be kept, got %+v", loaded)
+	}
+
+	if err := loaded.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCheckpoint(dir); !errors.Is(err, ErrNoCheckpoint) {
+		t.Errorf("Expected ErrNoCheckpoint after clear, got: %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/petersenjoern/devenv/internal/download"
+)
+
+const (
+	journalFile  = "journal.json"
+	backupsDir   = "backups"
+	backupLayout = "20060102-150405.000000000"
+
+	removeCmd  = "rm -f %s"
+	restoreCmd = "install -m %o %s %s"
+	sudoPrefix = "sudo "
+)
+
+// Kinds of filesystem changes recorded in the journal.
+const (
+	ChangeCreated  = "created"
+	ChangeReplaced = "replaced"
+)
+
+// ErrNoJournal is returned when no run has been journaled yet.
+var ErrNoJournal = errors.New("no previous run recorded")
+
+// CommandExecutor runs the shell commands that revert privileged changes.
+type CommandExecutor interface {
+	Execute(command string) error
+}
+
+// Change is a single file devenv created or replaced. Replaced files have a
+// copy of their previous content in Backup.
+type Change struct {
+	Tool   string      `json:"tool"`
+	Kind   string      `json:"kind"`
+	Path   string      `json:"path"`
+	Backup string      `json:"backup,omitempty"`
+	Mode   os.FileMode `json:"mode,omitempty"`
+	// Privileged changes were made with sudo and are reverted with sudo.
+	Privileged bool      `json:"privileged,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// Journal records the filesystem changes of the last install run so the run
+// can be rolled back. It is written to disk after every change. Each run
+// keeps its backups in its own directory; they are never removed by devenv.
+type Journal struct {
+	StartedAt  time.Time `json:"started_at"`
+	RolledBack bool      `json:"rolled_back,omitempty"`
+	BackupDir  string    `json:"backup_dir,omitempty"`
+	Changes    []Change  `json:"changes"`
+
+	dir string
+	mu  sync.Mutex
+}
+
+// StartJournal begins a new journal in dir, replacing the previous run's
+// journal. Backups of earlier runs are kept under backups/<start time>.
+func StartJournal(dir string) (*Journal, error) {
+	startedAt := time.Now().UTC()
+	journal := &Journal{
+		StartedAt: startedAt,
+		BackupDir: filepath.Join(dir, backupsDir, startedAt.Format(backupLayout)),
+		dir:       dir,
+	}
+	if err := journal.save(); err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// ResumeJournal continues the journal of an interrupted run in dir, or
+// starts a new one if there is none.
+func ResumeJournal(dir string) (*Journal, error) {
+	journal, err := LoadJournal(dir)
+	if errors.Is(err, ErrNoJournal) || (err == nil && journal.RolledBack) {
+		return StartJournal(dir)
+	}
+	return journal, err
+}
+
+// LoadJournal reads the journal of the last run from dir.
+func LoadJournal(dir string) (*Journal, error) {
+	data, err := os.ReadFile(filepath.Join(dir, journalFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoJournal
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	journal := &Journal{dir: dir}
+	if err := json.Unmarshal(data, journal); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+	return journal, nil
+}
+
+// Backup copies the current content of path into the journal's backup
+// directory and returns the copy's location.
+func (j *Journal) Backup(path string) (string, error) {
+	j.mu.Lock()
+	backupDir := j.BackupDir
+	if backupDir == "" {
+		// Journals written before backups were kept per run.
+		backupDir = filepath.Join(j.dir, backupsDir)
+	}
+	backup := filepath.Join(backupDir, strconv.Itoa(len(j.Changes))+"-"+filepath.Base(path))
+	j.mu.Unlock()
+
+	if err := download.CopyFile(path, backup); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	return backup, nil
+}
+
+// Record appends change to the journal and persists it.
+func (j *Journal) Record(change Change) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if change.Time.IsZero() {
+		change.Time = time.Now().UTC()
+	}
+	j.Changes = append(j.Changes, change)
+	return j.save()
+}
+
+// Rollback reverts the journaled changes in reverse order: created files are
+// removed and replaced files are restored from their backups. It continues
+// past failures and returns the changes that were reverted.
+func (j *Journal) Rollback(executor CommandExecutor) ([]Change, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var reverted []Change
+	var errs []error
+	for i := len(j.Changes) - 1; i >= 0; i-- {
+		change := j.Changes[i]
+		if err := executor.Execute(revertCommand(change)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to revert %s: %w", change.Path, err))
+			continue
+		}
+		reverted = append(reverted, change)
+	}
+
+	j.RolledBack = true
+	if err := j.save(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return reverted, errors.Join(errs...)
+}
+
+func revertCommand(change Change) string {
+	command := fmt.Sprintf(removeCmd, shellQuote(change.Path))
+	if change.Kind == ChangeReplaced {
+		mode := change.Mode.Perm()
+		if mode == 0 {
+			mode = 0o644
+		}
+		command = fmt.Sprintf(restoreCmd, mode, shellQuote(change.Backup), shellQuote(change.Path))
+	}
+
+	if change.Privileged {
+		return sudoPrefix + command
+	}
+	return command
+}
+
+func (j *Journal) save() error {
+	if err := os.MkdirAll(j.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+
+	tmp := filepath.Join(j.dir, journalFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(j.dir, journalFile))
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
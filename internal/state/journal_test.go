@@ -0,0 +1,126 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+type shellExecutor struct {
+	commands []string
+}
+
+func (s *shellExecutor) Execute(command string) error {
+	s.commands = append(s.commands, command)
+	return exec.Command("sh", "-c", command).Run()
+}
+
+func TestJournal_ShouldRollBackCreatedAndReplacedFiles(t *testing.T) {
+	stateDir := t.TempDir()
+	workDir := t.TempDir()
+
+	replaced := filepath.Join(workDir, ".tmux.conf")
+	if err := os.WriteFile(replaced, []byte("original"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(workDir, "bin", "broot")
+
+	journal, err := StartJournal(stateDir)
+	if err != nil {
+		t.Fatalf("Expected journal to start, got: %v", err)
+	}
+
+	backup, err := journal.Backup(replaced)
+	if err != nil {
+		t.Fatalf("Expected backup to succeed, got: %v", err)
+	}
+	if err := journal.Record(Change{Tool: "tmux", Kind: ChangeReplaced, Path: replaced, Backup: backup, Mode: 0o600}); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(replaced, []byte("from template"), 0o644)
+
+	if err := journal.Record(Change{Tool: "broot", Kind: ChangeCreated, Path: created}); err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Dir(created), 0o755)
+	os.WriteFile(created, []byte("binary"), 0o755)
+
+	// Rollback works from the journal on disk, as in a later devenv invocation
+	loaded, err := LoadJournal(stateDir)
+	if err != nil {
+		t.Fatalf("Expected journal to load, got: %v", err)
+	}
+
+	executor := &shellExecutor{}
+	reverted, err := loaded.Rollback(executor)
+	if err != nil {
+		t.Fatalf("Expected rollback to succeed, got: %v (commands %v)", err, executor.commands)
+	}
+
+	if len(reverted) != 2 || reverted[0].Path != created {
+		t.Errorf("Expected changes to be reverted newest first, got %+v", reverted)
+	}
+
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("Expected created file to be removed")
+	}
+
+	content, _ := os.ReadFile(replaced)
+	if string(content) != "original" {
+		t.Errorf("Expected replaced file to be restored, got %q", content)
+	}
+	if info, _ := os.Stat(replaced); info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected restored file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	reloaded, _ := LoadJournal(stateDir)
+	if !reloaded.RolledBack {
+		t.Errorf("Expected journal to be marked as rolled back")
+	}
+}
+
+func TestJournal_ShouldUseSudoForPrivilegedChanges(t *testing.T) {
+	command := revertCommand(Change{Kind: ChangeCreated, Path: "/usr/local/bin/broot", Privileged: true})
+
+	if command != "sudo rm -f '/usr/local/bin/broot'" {
+		t.Errorf("Expected privileged removal through sudo, got: %s", command)
+	}
+}
+
+func TestLoadJournal_ShouldReportMissingJournal(t *testing.T) {
+	if _, err := LoadJournal(t.TempDir()); !errors.Is(err, ErrNoJournal) {
+		t.Errorf("Expected ErrNoJournal, got: %v", err)
+	}
+}
+
+func TestStartJournal_ShouldKeepBackupsOfEarlierRuns(t *testing.T) {
+	stateDir := t.TempDir()
+	dotfile := filepath.Join(t.TempDir(), ".zshrc")
+	if err := os.WriteFile(dotfile, []byte("original"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := StartJournal(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup, err := first.Backup(dotfile)
+	if err != nil {
+		t.Fatalf("Expected backup to succeed, got: %v", err)
+	}
+
+	second, err := StartJournal(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.BackupDir == first.BackupDir {
+		t.Errorf("Expected each run to get its own backup directory, both use %s", first.BackupDir)
+	}
+
+	content, err := os.ReadFile(backup)
+	if err != nil || string(content) != "original" {
+		t.Errorf("Expected backup of the first run to survive a new journal, got %q: %v", content, err)
+	}
+}
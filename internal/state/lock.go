@@ -0,0 +1,91 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const lockFile = "devenv.lock"
+
+// LockedError is returned when another devenv run holds the lock.
+type LockedError struct {
+	PID int
+}
+
+func (e *LockedError) Error() string {
+	if e.PID <= 0 {
+		return "another devenv run is in progress"
+	}
+	return fmt.Sprintf("another devenv run (pid %d) is in progress", e.PID)
+}
+
+// Lock is an exclusive lock held by the running devenv process for the
+// duration of install, uninstall and upgrade runs.
+type Lock struct {
+	file *os.File
+}
+
+// AcquireLock takes an exclusive flock on the lock file in dir. The kernel
+// releases the lock when the process exits, so a lock left behind by a
+// crashed run is free again. The file is never removed; the pid written into
+// it only serves the error message of runs that find it locked.
+func AcquireLock(dir string) (*Lock, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	path := filepath.Join(dir, lockFile)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			pid, _ := readLockPID(path)
+			return nil, &LockedError{PID: pid}
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	if err := writeLockPID(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return &Lock{file: file}, nil
+}
+
+// Release unlocks the lock file.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	// Closing the file drops the flock.
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	l.file = nil
+	return nil
+}
+
+func writeLockPID(file *os.File) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return err
+}
+
+func readLockPID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,81 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquireLock_ShouldRejectConcurrentRun(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("Expected lock to be acquired, got: %v", err)
+	}
+	defer lock.Release()
+
+	_, err = AcquireLock(dir)
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Expected LockedError, got: %v", err)
+	}
+	if locked.PID != os.Getpid() {
+		t.Errorf("Expected lock holder pid %d, got %d", os.Getpid(), locked.PID)
+	}
+	if err.Error() != fmt.Sprintf("another devenv run (pid %d) is in progress", os.Getpid()) {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+func TestAcquireLock_ShouldTakeOverStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	// A lock file left behind by a crashed run is not locked any more
+	if err := os.WriteFile(filepath.Join(dir, lockFile), []byte("99999999\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("Expected stale lock to be taken over, got: %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Expected lock to be released, got: %v", err)
+	}
+
+	relock, err := AcquireLock(dir)
+	if err != nil {
+		t.Fatalf("Expected released lock to be acquirable again, got: %v", err)
+	}
+	relock.Release()
+}
+
+func TestAcquireLock_ShouldNotTakeOverLockWithUnreadablePID(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := AcquireLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Release()
+
+	// The holder has not written its pid yet, or wrote garbage
+	if err := os.WriteFile(filepath.Join(dir, lockFile), []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = AcquireLock(dir)
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Expected LockedError while the lock is held, got: %v", err)
+	}
+	if err.Error() != "another devenv run is in progress" {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, lockFile)); err != nil {
+		t.Errorf("Expected held lock file to be left in place, got: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const methodsFile = "methods.json"
+
+// Methods records which install method installed each tool and whether it
+// was installed in --user mode. Tools with a fallback chain are uninstalled
+// and upgraded with the method that actually installed them rather than the
+// first one of the chain, and user installs in the prefix they went to.
+type Methods struct {
+	Tools    map[string]string `json:"tools"`
+	UserMode map[string]bool   `json:"user_mode,omitempty"`
+
+	dir string
+	mu  sync.Mutex
+}
+
+// LoadMethods reads the recorded install methods from dir. A missing file
+// means no tool has been recorded yet.
+func LoadMethods(dir string) (*Methods, error) {
+	methods := &Methods{Tools: map[string]string{}, UserMode: map[string]bool{}, dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, methodsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return methods, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install methods: %w", err)
+	}
+
+	if err := json.Unmarshal(data, methods); err != nil {
+		return nil, fmt.Errorf("failed to parse install methods: %w", err)
+	}
+	if methods.Tools == nil {
+		methods.Tools = map[string]string{}
+	}
+	if methods.UserMode == nil {
+		methods.UserMode = map[string]bool{}
+	}
+	return methods, nil
+}
+
+// Method returns the method that installed toolName, or "" if unknown.
+func (m *Methods) Method(toolName string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Tools[toolName]
+}
+
+// InstalledInUserMode reports whether toolName was installed with --user.
+func (m *Methods) InstalledInUserMode(toolName string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.UserMode[toolName]
+}
+
+// RecordMethod remembers that method installed toolName, in --user mode when
+// userMode is set, and persists it.
+func (m *Methods) RecordMethod(toolName, method string, userMode bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Tools[toolName] == method && m.UserMode[toolName] == userMode {
+		return nil
+	}
+	m.Tools[toolName] = method
+	if userMode {
+		m.UserMode[toolName] = true
+	} else {
+		delete(m.UserMode, toolName)
+	}
+	return m.save()
+}
+
+// ForgetMethod drops toolName after it was uninstalled.
+func (m *Methods) ForgetMethod(toolName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.Tools[toolName]; !ok {
+		return nil
+	}
+	delete(m.Tools, toolName)
+	delete(m.UserMode, toolName)
+	return m.save()
+}
+
+func (m *Methods) save() error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode install methods: %w", err)
+	}
+
+	tmp := filepath.Join(m.dir, methodsFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write install methods: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(m.dir, methodsFile))
+}
@@ -0,0 +1,23 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const appDir = "devenv"
+
+// DefaultDir returns $XDG_STATE_HOME/devenv, falling back to
+// ~/.local/state/devenv.
+func DefaultDir() (string, error) {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, appDir), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", appDir), nil
+}
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/huh"
+	"github.com/petersenjoern/devenv/internal/config"
+)
+
+const (
+	manualDoneOption = "done"
+	manualSkipOption = "skip"
+)
+
+// ManualPrompt asks the user to confirm completion of a manual installation.
+type ManualPrompt struct {
+	Output io.Writer // Where the prompt is drawn; standard output when nil
+}
+
+func (p *ManualPrompt) AwaitCompletion(tool config.ToolConfig) (bool, error) {
+	choice := manualDoneOption
+
+	field := huh.NewSelect[string]().
+		Title(fmt.Sprintf("Install %s manually, then continue", tool.DisplayName)).
+		Options(
+			huh.NewOption("I have completed the installation", manualDoneOption),
+			huh.NewOption("Skip for now", manualSkipOption),
+		).
+		Value(&choice)
+	form := huh.NewForm(huh.NewGroup(field)).WithShowHelp(false)
+	if p.Output != nil {
+		form = form.WithOutput(p.Output)
+	}
+	if err := form.Run(); err != nil {
+		return false, fmt.Errorf("failed to run manual installation prompt: %w", err)
+	}
+
+	return choice == manualDoneOption, nil
+}
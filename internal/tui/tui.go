@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -11,6 +12,9 @@ import (
 
 type TUI struct {
 	config config.Config
+
+	// Output is where the forms are drawn; standard output when nil.
+	Output io.Writer
 }
 
 type Selections struct {
@@ -202,6 +206,9 @@ func (t *TUI) executeForm(form *InteractiveForm) (Selections, error) {
 	}
 
 	huhForm := huh.NewForm(form.groups...)
+	if t.Output != nil {
+		huhForm = huhForm.WithOutput(t.Output)
+	}
 	err := huhForm.Run()
 	if err != nil {
 		return Selections{}, fmt.Errorf("failed to run interactive form: %w", err)
@@ -1,15 +1,25 @@
 package main
 
 import (
+	"embed"
 	"fmt"
 	"os"
 
 	"github.com/petersenjoern/devenv/cmd"
+	"github.com/petersenjoern/devenv/internal/assets"
 )
 
+// Default catalog, install scripts and templates shipped with the binary so
+// devenv also works outside of the repository checkout.
+//
+//go:embed config.yaml install_scripts templates
+var embeddedAssets embed.FS
+
 func main() {
+	cmd.SetAssets(assets.New(embeddedAssets))
+
 	if err := cmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cmd.ExitCode(err))
 	}
 }